@@ -65,5 +65,5 @@ func FetchOrSave(ctx context.Context, c Cache, key string, value interface{},
 	}
 
 	// after the building, fetch value again
-	return c.Fetch(ctx, key, val)
+	return c.Fetch(ctx, key, value)
 }
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"context"
+	"time"
+)
+
+// Revision is the audit record of the change of the configure item, the
+// values of the password items are redacted
+type Revision struct {
+	ID       int64     `json:"id"`
+	Key      string    `json:"key"`
+	Tenant   string    `json:"tenant,omitempty"`
+	OldValue string    `json:"old_value"`
+	NewValue string    `json:"new_value"`
+	Actor    string    `json:"actor"`
+	Reason   string    `json:"reason,omitempty"`
+	Time     time.Time `json:"time"`
+	// Deleted the override of the tenant is deleted by the change
+	Deleted bool `json:"deleted,omitempty"`
+}
+
+// HistoryQuery is the query of the revisions, the zero fields match any
+type HistoryQuery struct {
+	Key    string
+	Tenant string
+	Actor  string
+	Since  time.Time
+	Until  time.Time
+	// Limit the max count of the revisions returned, the latest ones are returned first
+	Limit int
+}
+
+// Auditor is implemented by the config manager which records the changes
+type Auditor interface {
+	// History returns the revisions matched by the query, the latest first
+	History(ctx context.Context, query *HistoryQuery) ([]*Revision, error)
+	// Rollback restores the configure item to the value of the revision
+	Rollback(ctx context.Context, id int64) error
+}
@@ -0,0 +1,258 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ling-server/core/encrypt"
+	"github.com/ling-server/core/errors"
+)
+
+const (
+	// BundleVersion the version of the bundle exported
+	BundleVersion = "v1"
+
+	// BundleJSON the json format of the bundle
+	BundleJSON = "json"
+	// BundleYAML the yaml format of the bundle
+	BundleYAML = "yaml"
+)
+
+const (
+	// PasswordEncrypt the passwords are encrypted in the bundle
+	PasswordEncrypt = "encrypt"
+	// PasswordOmit the passwords are omitted from the bundle
+	PasswordOmit = "omit"
+)
+
+const (
+	// ConflictOverwrite the values in the bundle overwrite the different values persisted
+	ConflictOverwrite = "overwrite"
+	// ConflictSkip the values persisted are kept when they are different from the ones in the bundle
+	ConflictSkip = "skip"
+)
+
+// BundleItem is the configure value in the bundle
+type BundleItem struct {
+	Name  string `json:"name" yaml:"name"`
+	Value string `json:"value" yaml:"value"`
+	// Encrypted the value is encrypted
+	Encrypted bool `json:"encrypted,omitempty" yaml:"encrypted,omitempty"`
+}
+
+// Bundle is the versioned export of the user scope configure values
+type Bundle struct {
+	Version    string        `json:"version" yaml:"version"`
+	ExportedAt time.Time     `json:"exported_at" yaml:"exported_at"`
+	Items      []*BundleItem `json:"items" yaml:"items"`
+}
+
+// Encode encodes the bundle in the format, json or yaml
+func (b *Bundle) Encode(format string) ([]byte, error) {
+	switch format {
+	case BundleJSON:
+		return json.MarshalIndent(b, "", "  ")
+	case BundleYAML:
+		return yaml.Marshal(b)
+	default:
+		return nil, fmt.Errorf("unsupported bundle format: %s", format)
+	}
+}
+
+// DecodeBundle decodes the bundle in the format, json or yaml
+func DecodeBundle(data []byte, format string) (*Bundle, error) {
+	b := &Bundle{}
+	var err error
+	switch format {
+	case BundleJSON:
+		err = json.Unmarshal(data, b)
+	case BundleYAML:
+		err = yaml.Unmarshal(data, b)
+	default:
+		return nil, fmt.Errorf("unsupported bundle format: %s", format)
+	}
+	if err != nil {
+		return nil, errors.BadRequestError(err).WithMessage("invalid bundle")
+	}
+	return b, nil
+}
+
+// ExportOptions the options of the export
+type ExportOptions struct {
+	// Password the policy of the passwords, PasswordEncrypt or PasswordOmit, the
+	// passwords are omitted by default
+	Password string
+	// Encryptor encrypts the passwords, encrypt.AesInstance() is used by default
+	Encryptor encrypt.Encryptor
+}
+
+// Export exports the editable user scope configure values of the manager into
+// the bundle, the items which are not editable can't be imported so they are
+// not exported
+func Export(ctx context.Context, mgr Manager, opts *ExportOptions) (*Bundle, error) {
+	if opts == nil {
+		opts = &ExportOptions{}
+	}
+	if opts.Encryptor == nil {
+		opts.Encryptor = encrypt.AesInstance()
+	}
+
+	b := &Bundle{
+		Version:    BundleVersion,
+		ExportedAt: time.Now().UTC(),
+		Items:      make([]*BundleItem, 0),
+	}
+	for _, item := range Instance().GetByScope(UserScope) {
+		if !editable(&item) {
+			continue
+		}
+		bi := &BundleItem{Name: item.Name, Value: mgr.Get(ctx, item.Name).Value}
+		// the item has no value, e.g. the int item without default value
+		if len(bi.Value) == 0 && item.Validate(bi.Value) != nil {
+			continue
+		}
+
+		if _, isPassword := item.ItemType.(*PasswordType); isPassword && len(bi.Value) > 0 {
+			if opts.Password != PasswordEncrypt {
+				continue
+			}
+
+			value, err := opts.Encryptor.Encrypt(bi.Value)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to encrypt the configure item %s", item.Name)
+			}
+			bi.Value, bi.Encrypted = value, true
+		}
+
+		b.Items = append(b.Items, bi)
+	}
+
+	return b, nil
+}
+
+// ImportOptions the options of the import
+type ImportOptions struct {
+	// Conflict the policy when the value is different from the persisted one,
+	// ConflictOverwrite or ConflictSkip, the values are skipped by default
+	Conflict string
+	// Encryptor decrypts the encrypted values, encrypt.AesInstance() is used by default
+	Encryptor encrypt.Encryptor
+	// DryRun returns the report without importing the values
+	DryRun bool
+	// Partial imports the valid values when some values in the bundle are
+	// rejected, nothing is imported in this case by default
+	Partial bool
+}
+
+// ImportReport is the report of the import, the keys are sorted
+type ImportReport struct {
+	// Imported the keys of the values added or changed
+	Imported []string `json:"imported"`
+	// Skipped the keys of the values which are skipped because of the conflict
+	Skipped []string `json:"skipped"`
+	// Unchanged the keys of the values which are the same as the current ones
+	Unchanged []string `json:"unchanged"`
+	// Rejected the values which are invalid
+	Rejected []*Change `json:"rejected"`
+}
+
+// Import validates and imports the values in the bundle into the manager, the
+// conflicts are detected by the dry-run of the manager if it is a DryRunner,
+// otherwise the values different from the default are treated as persisted.
+// The values are imported by one UpdateConfig, nothing is imported when the
+// update fails or any value is rejected and the Partial is not set, the bad
+// request error is returned with the report in the latter case
+func Import(ctx context.Context, mgr Manager, b *Bundle, opts *ImportOptions) (*ImportReport, error) {
+	if opts == nil {
+		opts = &ImportOptions{}
+	}
+	if opts.Encryptor == nil {
+		opts.Encryptor = encrypt.AesInstance()
+	}
+	if b.Version != BundleVersion {
+		return nil, errors.BadRequestError(nil).WithMessage("unsupported bundle version: %s", b.Version)
+	}
+
+	cfgs := make(map[string]interface{}, len(b.Items))
+	for _, bi := range b.Items {
+		value := bi.Value
+		if bi.Encrypted {
+			decrypted, err := opts.Encryptor.Decrypt(value)
+			if err != nil {
+				return nil, errors.BadRequestError(err).WithMessage("failed to decrypt the configure item %s", bi.Name)
+			}
+			value = decrypted
+		}
+		cfgs[bi.Name] = value
+	}
+
+	plan, err := planImport(ctx, mgr, cfgs)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ImportReport{
+		Imported:  make([]string, 0),
+		Skipped:   make([]string, 0),
+		Unchanged: make([]string, 0),
+		Rejected:  make([]*Change, 0),
+	}
+	values := make(map[string]interface{})
+	for _, c := range plan.Changes {
+		switch c.Action {
+		case ActionRejected:
+			report.Rejected = append(report.Rejected, c)
+		case ActionUnchanged:
+			report.Unchanged = append(report.Unchanged, c.Key)
+		case ActionChanged:
+			if opts.Conflict != ConflictOverwrite {
+				report.Skipped = append(report.Skipped, c.Key)
+				continue
+			}
+			fallthrough
+		default:
+			report.Imported = append(report.Imported, c.Key)
+			values[c.Key] = cfgs[c.Key]
+		}
+	}
+
+	if opts.DryRun {
+		return report, nil
+	}
+	if len(report.Rejected) > 0 && !opts.Partial {
+		keys := make([]string, 0, len(report.Rejected))
+		for _, c := range report.Rejected {
+			keys = append(keys, c.Key)
+		}
+		return report, errors.BadRequestError(nil).WithMessage("the configure items are rejected, nothing is imported: %s", strings.Join(keys, ", "))
+	}
+	if len(values) == 0 {
+		return report, nil
+	}
+
+	if len(ReasonFromContext(ctx)) == 0 {
+		ctx = WithReason(ctx, fmt.Sprintf("import the bundle exported at %s", b.ExportedAt.Format(time.RFC3339)))
+	}
+	if err := mgr.UpdateConfig(ctx, values); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+func planImport(ctx context.Context, mgr Manager, cfgs map[string]interface{}) (*Plan, error) {
+	if r, ok := mgr.(DryRunner); ok {
+		return r.DryRun(ctx, cfgs)
+	}
+
+	return NewPlan(cfgs, func(key string) (string, bool) {
+		value := mgr.Get(ctx, key).Value
+		item, ok := Instance().GetByName(key)
+		return value, ok && value != item.DefaultValue
+	}), nil
+}
@@ -57,7 +57,14 @@ func DefaultManager() Manager {
 	return manager
 }
 
+// GetConfigManager returns the config manager in the context, the default
+// manager is returned when there is no manager in the context
 func GetConfigManager(ctx context.Context) Manager {
+	if ctx != nil {
+		if mgr, ok := FromContext(ctx); ok {
+			return mgr
+		}
+	}
 	return DefaultManager()
 }
 
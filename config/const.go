@@ -3,3 +3,12 @@ package config
 const (
 	DBConfigManager = "config.manager.db"
 )
+
+const (
+	// SystemScope the scope of the configure items which are set by the
+	// environment when the system starts, they can't be changed by the api
+	SystemScope = "system"
+	// UserScope the scope of the configure items which are editable and
+	// persisted in the store
+	UserScope = "user"
+)
@@ -0,0 +1,164 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Constraint is the declarative rule of the configure value, it is checked
+// after the value passes the validation of the item type
+type Constraint interface {
+	// Check returns error when the value violates the constraint
+	Check(value string) error
+}
+
+// MinConstraint requires the numeric value is not less than Min
+type MinConstraint struct {
+	Min float64
+}
+
+// Min returns the constraint of the minimum numeric value
+func Min(min float64) Constraint {
+	return &MinConstraint{Min: min}
+}
+
+// Check ...
+func (c *MinConstraint) Check(value string) error {
+	val, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fmt.Errorf("%q is not a number", value)
+	}
+	if val < c.Min {
+		return fmt.Errorf("should be greater than or equal to %v", c.Min)
+	}
+	return nil
+}
+
+// MaxConstraint requires the numeric value is not greater than Max
+type MaxConstraint struct {
+	Max float64
+}
+
+// Max returns the constraint of the maximum numeric value
+func Max(max float64) Constraint {
+	return &MaxConstraint{Max: max}
+}
+
+// Check ...
+func (c *MaxConstraint) Check(value string) error {
+	val, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fmt.Errorf("%q is not a number", value)
+	}
+	if val > c.Max {
+		return fmt.Errorf("should be less than or equal to %v", c.Max)
+	}
+	return nil
+}
+
+// PatternConstraint requires the value matches the regular expression
+type PatternConstraint struct {
+	Pattern *regexp.Regexp
+}
+
+// Pattern returns the constraint of the regular expression, it panics when
+// the expression can't be compiled as the items are defined at the init time
+func Pattern(expr string) Constraint {
+	return &PatternConstraint{Pattern: regexp.MustCompile(expr)}
+}
+
+// Check ...
+func (c *PatternConstraint) Check(value string) error {
+	if !c.Pattern.MatchString(value) {
+		return fmt.Errorf("should match the pattern %s", c.Pattern.String())
+	}
+	return nil
+}
+
+// EnumConstraint requires the value is one of the Values
+type EnumConstraint struct {
+	Values []string
+}
+
+// Enum returns the constraint of the allowed values
+func Enum(values ...string) Constraint {
+	return &EnumConstraint{Values: values}
+}
+
+// Check ...
+func (c *EnumConstraint) Check(value string) error {
+	for _, v := range c.Values {
+		if v == value {
+			return nil
+		}
+	}
+	return fmt.Errorf("should be one of [%s]", strings.Join(c.Values, ", "))
+}
+
+// URLConstraint requires the value is an absolute url, the scheme of the url
+// should be one of the Schemes if they are specified
+type URLConstraint struct {
+	Schemes []string
+}
+
+// URL returns the constraint of the url with the allowed schemes
+func URL(schemes ...string) Constraint {
+	return &URLConstraint{Schemes: schemes}
+}
+
+// Check ...
+func (c *URLConstraint) Check(value string) error {
+	u, err := url.Parse(value)
+	if err != nil || len(u.Scheme) == 0 || len(u.Host) == 0 {
+		return fmt.Errorf("%q is not a valid url", value)
+	}
+	if len(c.Schemes) == 0 {
+		return nil
+	}
+	for _, scheme := range c.Schemes {
+		if strings.EqualFold(scheme, u.Scheme) {
+			return nil
+		}
+	}
+	return fmt.Errorf("the scheme of the url should be one of [%s]", strings.Join(c.Schemes, ", "))
+}
+
+// DurationConstraint requires the value is a duration, e.g. 30s, 1h30m, in the
+// range of Min and Max, the zero Min or Max means no limit
+type DurationConstraint struct {
+	Min time.Duration
+	Max time.Duration
+}
+
+// Duration returns the constraint of the duration in the range of min and max
+func Duration(min, max time.Duration) Constraint {
+	return &DurationConstraint{Min: min, Max: max}
+}
+
+// Check ...
+func (c *DurationConstraint) Check(value string) error {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("%q is not a valid duration", value)
+	}
+	if c.Min != 0 && d < c.Min {
+		return fmt.Errorf("should be greater than or equal to %s", c.Min)
+	}
+	if c.Max != 0 && d > c.Max {
+		return fmt.Errorf("should be less than or equal to %s", c.Max)
+	}
+	return nil
+}
+
+// Rule is the cross-field validation rule, it is checked when any of the Keys
+// is changed, with the effective values of the Keys after the change
+type Rule struct {
+	// Keys the configure items the rule depends on
+	Keys []string
+	// Check returns error when the values violate the rule
+	Check func(values map[string]string) error
+}
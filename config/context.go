@@ -14,3 +14,45 @@ func FromContext(ctx context.Context) (Manager, bool) {
 func NewContext(ctx context.Context, m Manager) context.Context {
 	return context.WithValue(ctx, configManagerKey{}, m)
 }
+
+type actorKey struct{}
+
+type reasonKey struct{}
+
+// WithActor returns context with the actor who changes the configure values
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor from context, empty string is returned
+// when there is no actor in the context
+func ActorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
+
+// WithReason returns context with the reason of the change of configure values
+func WithReason(ctx context.Context, reason string) context.Context {
+	return context.WithValue(ctx, reasonKey{}, reason)
+}
+
+// ReasonFromContext returns the reason from context
+func ReasonFromContext(ctx context.Context) string {
+	reason, _ := ctx.Value(reasonKey{}).(string)
+	return reason
+}
+
+type tenantKey struct{}
+
+// WithTenant returns context with the tenant, the configure values are
+// resolved for the tenant by the config manager
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant from context, false is returned when
+// there is no tenant in the context
+func TenantFromContext(ctx context.Context) (string, bool) {
+	tenant, ok := ctx.Value(tenantKey{}).(string)
+	return tenant, ok && len(tenant) > 0
+}
@@ -0,0 +1,112 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/ling-server/core/config"
+	"github.com/ling-server/core/errors"
+)
+
+// History returns the revisions of the configure values matched by the query,
+// the latest revisions are returned first
+func (m *Manager) History(ctx context.Context, query *config.HistoryQuery) ([]*config.Revision, error) {
+	tx := m.db.WithContext(ctx).Model(&PropertyRevision{})
+	if query != nil {
+		if len(query.Key) > 0 {
+			tx = tx.Where("k = ?", query.Key)
+		}
+		if len(query.Tenant) > 0 {
+			tx = tx.Where("tenant = ?", query.Tenant)
+		}
+		if len(query.Actor) > 0 {
+			tx = tx.Where("actor = ?", query.Actor)
+		}
+		if !query.Since.IsZero() {
+			tx = tx.Where("creation_time >= ?", query.Since)
+		}
+		if !query.Until.IsZero() {
+			tx = tx.Where("creation_time <= ?", query.Until)
+		}
+		if query.Limit > 0 {
+			tx = tx.Limit(query.Limit)
+		}
+	}
+
+	records := make([]*PropertyRevision, 0)
+	if err := tx.Order("id desc").Find(&records).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to query the revisions of the configure values")
+	}
+
+	revisions := make([]*config.Revision, 0, len(records))
+	for _, r := range records {
+		revisions = append(revisions, r.toRevision())
+	}
+	return revisions, nil
+}
+
+// Rollback restores the configure item to the value after the change of the
+// revision, the override of the tenant is restored for the revision of tenant
+// and deleted again for the revision of the deletion. The rollback is recorded
+// as a new revision, the password items can't be rolled back as their values
+// are redacted in the revisions
+func (m *Manager) Rollback(ctx context.Context, id int64) error {
+	r := &PropertyRevision{}
+	err := m.db.WithContext(ctx).First(r, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return errors.NotFoundError(err).WithMessage("the revision %d is not found", id)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the revision %d", id)
+	}
+
+	item, ok := config.Instance().GetByName(r.Key)
+	if !ok {
+		return errors.BadRequestError(config.ErrorNotDefined).WithMessage("the configure item %s is not defined", r.Key)
+	}
+	if _, isPassword := item.ItemType.(*config.PasswordType); isPassword {
+		return errors.BadRequestError(nil).WithMessage("the password item %s can't be rolled back", r.Key)
+	}
+
+	if len(r.Tenant) > 0 {
+		ctx = config.WithTenant(ctx, r.Tenant)
+	}
+	if len(config.ReasonFromContext(ctx)) == 0 {
+		ctx = config.WithReason(ctx, fmt.Sprintf("rollback to revision %d", id))
+	}
+
+	if r.Deleted {
+		return m.DeleteOverrides(ctx, r.Key)
+	}
+	return m.UpdateConfig(ctx, map[string]interface{}{r.Key: r.NewValue})
+}
+
+// audit records the revisions of the changes in the transaction, deleted
+// marks the changes as the deletions of the overrides
+func (m *Manager) audit(ctx context.Context, tx *gorm.DB, events []*config.Event, deleted bool) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	actor, reason := config.ActorFromContext(ctx), config.ReasonFromContext(ctx)
+	records := make([]*PropertyRevision, 0, len(events))
+	for _, e := range events {
+		records = append(records, &PropertyRevision{
+			Key:          e.Key,
+			Tenant:       e.Tenant,
+			OldValue:     e.OldValue,
+			NewValue:     e.NewValue,
+			Actor:        actor,
+			Reason:       reason,
+			CreationTime: e.Time,
+			Deleted:      deleted,
+		})
+	}
+
+	if err := tx.Create(&records).Error; err != nil {
+		return errors.Wrap(err, "failed to record the revisions of the configure values")
+	}
+	return nil
+}
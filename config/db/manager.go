@@ -0,0 +1,635 @@
+package db
+
+import (
+	"context"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/ling-server/core/cache"
+	_ "github.com/ling-server/core/cache/memory" // the fallback cache
+	"github.com/ling-server/core/config"
+	"github.com/ling-server/core/encrypt"
+	"github.com/ling-server/core/errors"
+	"github.com/ling-server/core/log"
+)
+
+var _ config.Manager = (*Manager)(nil)
+var _ config.Watcher = (*Manager)(nil)
+var _ config.Auditor = (*Manager)(nil)
+var _ config.DryRunner = (*Manager)(nil)
+var _ config.Persistent = (*Manager)(nil)
+
+const cachePrefix = "config:"
+
+// Manager is the config manager which persists the user scope configure values
+// in the database, the password values are encrypted before they are saved and
+// the reads are cached in the cache. The user scope values can be overridden for
+// the tenant in the context, the value is resolved in the order of the tenant
+// override, the global value and the default value.
+type Manager struct {
+	id          string // the id of the manager instance which is the origin of the change events
+	db          *gorm.DB
+	opts        Options
+	mu          sync.Mutex                        // guards pending and saving, it is not held when accessing the database
+	smu         sync.Mutex                        // serializes the saves
+	pending     map[string]*config.ConfigureValue // the values set but not saved
+	saving      map[string]*config.ConfigureValue // the values being saved by Save
+	broadcaster *config.Broadcaster
+}
+
+// NewManager returns the database config manager, the default cache is used to
+// cache the configure values when no cache is specified by the options, and the
+// memory cache is used when the default cache is not initialized.
+func NewManager(db *gorm.DB, opt ...Option) (*Manager, error) {
+	opts := newOptions(opt...)
+
+	if opts.Cache == nil {
+		opts.Cache = cache.Default()
+	}
+
+	if opts.Cache == nil {
+		c, err := cache.New(cache.Memory)
+		if err != nil {
+			return nil, err
+		}
+		opts.Cache = c
+	}
+
+	if opts.Encryptor == nil {
+		opts.Encryptor = encrypt.AesInstance()
+	}
+
+	return &Manager{
+		id:          uuid.New().String(),
+		db:          db,
+		opts:        opts,
+		pending:     map[string]*config.ConfigureValue{},
+		broadcaster: config.NewBroadcaster(),
+	}, nil
+}
+
+// Initialize migrates the table of the configure values, creates the database
+// config manager and registers it as the config.DBConfigManager
+func Initialize(db *gorm.DB, opt ...Option) (*Manager, error) {
+	if err := db.AutoMigrate(&Property{}, &TenantProperty{}, &PropertyRevision{}); err != nil {
+		return nil, errors.Wrap(err, "failed to migrate the table of configure values")
+	}
+
+	mgr, err := NewManager(db, opt...)
+	if err != nil {
+		return nil, err
+	}
+
+	config.Register(config.DBConfigManager, mgr)
+	return mgr, nil
+}
+
+// Load loads all the configure values from the database into the cache, and
+// resolves the values of the lower layers by the resolver
+func (m *Manager) Load(ctx context.Context) error {
+	if m.opts.Resolver != nil {
+		if _, err := m.opts.Resolver.Resolve(ctx); err != nil {
+			return errors.Wrap(err, "failed to resolve the configure values")
+		}
+	}
+
+	props := make([]*Property, 0)
+	if err := m.db.WithContext(ctx).Find(&props).Error; err != nil {
+		return errors.Wrap(err, "failed to load the configure values")
+	}
+
+	for _, p := range props {
+		if err := m.opts.Cache.Save(ctx, cacheKey(p.Key), p.Value, m.expiration()...); err != nil {
+			log.Warningf("failed to cache the configure value of %s, error: %v", p.Key, err)
+		}
+	}
+
+	return nil
+}
+
+// Source returns the persistent store layer of the configure values, the
+// password values in the layer are decrypted
+func (m *Manager) Source() config.Source {
+	return config.NewSource(config.StoreLayer, func(ctx context.Context) (map[string]string, error) {
+		props := make([]*Property, 0)
+		if err := m.db.WithContext(ctx).Find(&props).Error; err != nil {
+			return nil, errors.Wrap(err, "failed to load the configure values")
+		}
+
+		values := make(map[string]string, len(props))
+		for _, p := range props {
+			item, ok := config.Instance().GetByName(p.Key)
+			if !ok {
+				continue
+			}
+
+			value, err := m.decrypt(item, p.Value)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to decrypt the configure item %s", p.Key)
+			}
+			values[p.Key] = value
+		}
+		return values, nil
+	})
+}
+
+// Set sets the global configure value, the value will be persisted by Save
+func (m *Manager) Set(ctx context.Context, key string, value interface{}) {
+	str, err := config.StringValue(value)
+	if err != nil {
+		log.Errorf("failed to set the configure value of %s, error: %v", key, err)
+		return
+	}
+
+	cv, err := config.NewConfigureValue(key, str)
+	if err != nil {
+		log.Errorf("failed to set the configure value of %s, error: %v", key, err)
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.pending[key] = cv
+}
+
+// Save persists the values set by Set, the values are kept to be saved again
+// when it fails
+func (m *Manager) Save(ctx context.Context) error {
+	m.smu.Lock()
+	defer m.smu.Unlock()
+
+	m.mu.Lock()
+	values := m.pending
+	m.pending = map[string]*config.ConfigureValue{}
+	m.saving = values
+	m.mu.Unlock()
+
+	err := m.save(ctx, "", values)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.saving = nil
+	if err != nil {
+		// the values set during saving are newer
+		for key, cv := range values {
+			if _, ok := m.pending[key]; !ok {
+				m.pending[key] = cv
+			}
+		}
+	}
+	return err
+}
+
+// Get returns the configure value of the key, the value set but not saved is
+// returned first, then the override of the tenant in the ctx, the global value
+// in the cache or database, and the default value when it is not persisted.
+func (m *Manager) Get(ctx context.Context, key string) *config.ConfigureValue {
+	m.mu.Lock()
+	cv, ok := m.pending[key]
+	if !ok {
+		cv, ok = m.saving[key]
+	}
+	m.mu.Unlock()
+	if ok {
+		return &config.ConfigureValue{Name: cv.Name, Value: cv.Value}
+	}
+
+	value, err := m.get(ctx, key)
+	if err != nil {
+		log.Errorf("failed to get the configure value of %s, error: %v", key, err)
+	}
+
+	return &config.ConfigureValue{Name: key, Value: value}
+}
+
+// UpdateConfig validates and persists the configure values, the values are
+// persisted as the overrides of the tenant when there is tenant in the ctx, the
+// forbidden error is returned when any of the items is not editable
+func (m *Manager) UpdateConfig(ctx context.Context, cfgs map[string]interface{}) error {
+	if err := config.CheckEditable(cfgs); err != nil {
+		return err
+	}
+
+	if err := m.ValidateConfig(ctx, cfgs); err != nil {
+		return err
+	}
+
+	values := make(map[string]*config.ConfigureValue, len(cfgs))
+	for key, value := range cfgs {
+		str, err := config.StringValue(value)
+		if err != nil {
+			return err
+		}
+		values[key] = &config.ConfigureValue{Name: key, Value: str}
+	}
+
+	tenant, _ := config.TenantFromContext(ctx)
+	return m.save(ctx, tenant, values)
+}
+
+// DeleteOverrides deletes the overrides of the keys for the tenant in the ctx,
+// the values of the keys fall back to the global values
+func (m *Manager) DeleteOverrides(ctx context.Context, keys ...string) error {
+	tenant, ok := config.TenantFromContext(ctx)
+	if !ok {
+		return errors.BadRequestError(nil).WithMessage("no tenant in the context")
+	}
+
+	// the deletions are recorded and published even if the values are not changed
+	events := make([]*config.Event, 0, len(keys))
+	err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, key := range keys {
+			oldValue, err := m.get(ctx, key)
+			if err != nil {
+				return err
+			}
+
+			result := tx.Where("tenant = ? AND k = ?", tenant, key).Delete(&TenantProperty{})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				continue
+			}
+
+			newValue, err := m.get(config.WithTenant(ctx, ""), key)
+			if err != nil {
+				return err
+			}
+			e := config.NewEvent(key, oldValue, newValue, m.id)
+			e.Tenant = tenant
+			e.Unchanged = oldValue == newValue
+			events = append(events, e)
+		}
+
+		return m.audit(ctx, tx, events, true)
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete the configure overrides of %s", tenant)
+	}
+
+	for _, key := range keys {
+		if err := m.opts.Cache.Delete(ctx, tenantCacheKey(tenant, key)); err != nil {
+			log.Errorf("failed to invalidate the cached configure value of %s for %s, error: %v", key, tenant, err)
+		}
+	}
+
+	m.notify(ctx, events)
+	return nil
+}
+
+// GetUserConfigs returns the values of the user scope configure items
+func (m *Manager) GetUserConfigs(ctx context.Context) map[string]interface{} {
+	return m.getAll(ctx, config.Instance().GetByScope(config.UserScope))
+}
+
+// ValidateConfig validates the configure values against the metadata, all the
+// violations are returned as errors.Errors
+func (m *Manager) ValidateConfig(ctx context.Context, cfgs map[string]interface{}) error {
+	return config.ValidateValues(cfgs, func(key string) string {
+		return m.Get(ctx, key).Value
+	})
+}
+
+// GetAll returns the values of all the configure items
+func (m *Manager) GetAll(ctx context.Context) map[string]interface{} {
+	return m.getAll(ctx, config.Instance().GetAll())
+}
+
+// Watch returns the change events of the keys which are saved by this manager,
+// and the events received by Sync from other instances
+func (m *Manager) Watch(ctx context.Context, keys ...string) (<-chan *config.Event, error) {
+	return m.broadcaster.Watch(ctx, keys...)
+}
+
+// Sync receives the change events from the watcher until the ctx is done, the
+// changes made by other instances invalidate the cached values and are
+// delivered to the subscribers of this manager, so that the replicas converge
+// without restart, e.g. go mgr.Sync(ctx, pubsub)
+func (m *Manager) Sync(ctx context.Context, w config.Watcher) error {
+	events, err := w.Watch(ctx)
+	if err != nil {
+		return err
+	}
+
+	for e := range events {
+		// the changes made by this instance are handled when saving
+		if e.Origin == m.id {
+			continue
+		}
+
+		if err := m.opts.Cache.Delete(ctx, tenantCacheKey(e.Tenant, e.Key)); err != nil {
+			log.Errorf("failed to invalidate the cached configure value of %s, error: %v", e.Key, err)
+		}
+
+		// the event of the unchanged value only invalidates the cache
+		if e.Unchanged {
+			continue
+		}
+		if err := m.broadcaster.Notify(ctx, e); err != nil {
+			log.Errorf("failed to notify the change of %s, error: %v", e.Key, err)
+		}
+	}
+
+	return nil
+}
+
+func (m *Manager) getAll(ctx context.Context, items []config.Item) map[string]interface{} {
+	result := map[string]interface{}{}
+	for _, item := range items {
+		val, err := m.Get(ctx, item.Name).GetAnyType()
+		if err != nil {
+			log.Errorf("failed to get the value of the configure item %s, error: %v", item.Name, err)
+			continue
+		}
+		result[item.Name] = val
+	}
+
+	return result
+}
+
+func (m *Manager) get(ctx context.Context, key string) (string, error) {
+	item, ok := config.Instance().GetByName(key)
+	if !ok {
+		return "", config.ErrorNotDefined
+	}
+
+	// only the user scope values are persisted in the database
+	if item.Scope != config.UserScope {
+		return m.defaultValue(item), nil
+	}
+
+	if tenant, ok := config.TenantFromContext(ctx); ok {
+		value, found, err := m.getOverride(ctx, tenant, item)
+		if err != nil {
+			return "", err
+		}
+		if found {
+			return value, nil
+		}
+	}
+
+	var value string
+	err := cache.FetchOrSave(ctx, m.opts.Cache, cacheKey(key), &value, func() (interface{}, error) {
+		p := &Property{}
+		err := m.db.WithContext(ctx).Where("k = ?", key).First(p).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return m.defaultValue(item), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return p.Value, nil
+	}, m.expiration()...)
+	if err != nil {
+		return "", err
+	}
+
+	return m.decrypt(item, value)
+}
+
+// override is the cached override of the tenant, the Found is false when the
+// tenant doesn't override the value
+type override struct {
+	Value string
+	Found bool
+}
+
+func (m *Manager) getOverride(ctx context.Context, tenant string, item *config.Item) (string, bool, error) {
+	o := &override{}
+	err := cache.FetchOrSave(ctx, m.opts.Cache, tenantCacheKey(tenant, item.Name), o, func() (interface{}, error) {
+		p := &TenantProperty{}
+		err := m.db.WithContext(ctx).Where("tenant = ? AND k = ?", tenant, item.Name).First(p).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &override{}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &override{Value: p.Value, Found: true}, nil
+	}, m.expiration()...)
+	if err != nil || !o.Found {
+		return "", false, err
+	}
+
+	value, err := m.decrypt(item, o.Value)
+	return value, true, err
+}
+
+// save persists the values as the overrides of the tenant, or the global values
+// when the tenant is empty
+func (m *Manager) save(ctx context.Context, tenant string, values map[string]*config.ConfigureValue) error {
+	if len(tenant) > 0 {
+		ctx = config.WithTenant(ctx, tenant)
+	} else {
+		// the old values are the global ones
+		ctx = config.WithTenant(ctx, "")
+	}
+
+	props := make([]*Property, 0, len(values))
+	events := make([]*config.Event, 0, len(values))
+	for key, cv := range values {
+		item, ok := config.Instance().GetByName(key)
+		if !ok {
+			return errors.Wrapf(config.ErrorNotDefined, "failed to save the configure item %s", key)
+		}
+
+		if item.Scope != config.UserScope {
+			log.Warningf("the configure item %s is not in user scope, skip to save it", key)
+			continue
+		}
+
+		value, err := m.encrypt(item, cv.Value)
+		if err != nil {
+			return errors.Wrapf(err, "failed to encrypt the configure item %s", key)
+		}
+
+		props = append(props, &Property{Key: key, Value: value})
+
+		oldValue, err := m.get(ctx, key)
+		if err != nil {
+			log.Warningf("failed to get the old value of %s, error: %v", key, err)
+		}
+		// the unchanged values are published too so that the replicas invalidate the caches
+		e := config.NewEvent(key, oldValue, cv.Value, m.id)
+		e.Tenant = tenant
+		e.Unchanged = oldValue == cv.Value
+		events = append(events, e)
+	}
+
+	if len(props) == 0 {
+		return nil
+	}
+
+	err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if len(tenant) > 0 {
+			overrides := make([]*TenantProperty, 0, len(props))
+			for _, p := range props {
+				overrides = append(overrides, &TenantProperty{Tenant: tenant, Key: p.Key, Value: p.Value})
+			}
+			err = tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "tenant"}, {Name: "k"}},
+				DoUpdates: clause.AssignmentColumns([]string{"v"}),
+			}).Create(&overrides).Error
+		} else {
+			err = tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "k"}},
+				DoUpdates: clause.AssignmentColumns([]string{"v"}),
+			}).Create(&props).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		return m.audit(ctx, tx, changed(events), false)
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to save the configure values")
+	}
+
+	for _, p := range props {
+		if err := m.opts.Cache.Delete(ctx, tenantCacheKey(tenant, p.Key)); err != nil {
+			log.Errorf("failed to invalidate the cached configure value of %s, error: %v", p.Key, err)
+		}
+	}
+
+	m.notify(ctx, events)
+	return nil
+}
+
+// notify delivers the changed values to the subscribers of this manager, and
+// publishes all the events to the notifiers for the replicas
+func (m *Manager) notify(ctx context.Context, events []*config.Event) {
+	if changes := changed(events); len(changes) > 0 {
+		if err := m.broadcaster.Notify(ctx, changes...); err != nil {
+			log.Errorf("failed to notify the config changes, error: %v", err)
+		}
+	}
+
+	if len(events) == 0 {
+		return
+	}
+	for _, n := range m.opts.Notifiers {
+		if err := n.Notify(ctx, events...); err != nil {
+			log.Errorf("failed to notify the config changes, error: %v", err)
+		}
+	}
+}
+
+// changed returns the events whose values are changed
+func changed(events []*config.Event) []*config.Event {
+	result := make([]*config.Event, 0, len(events))
+	for _, e := range events {
+		if !e.Unchanged {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+func (m *Manager) encrypt(item *config.Item, value string) (string, error) {
+	if _, ok := item.ItemType.(*config.PasswordType); !ok || len(value) == 0 {
+		return value, nil
+	}
+
+	return m.opts.Encryptor.Encrypt(value)
+}
+
+func (m *Manager) decrypt(item *config.Item, value string) (string, error) {
+	if _, ok := item.ItemType.(*config.PasswordType); !ok {
+		return value, nil
+	}
+
+	// the default value of the password is not encrypted
+	if !strings.HasPrefix(value, encrypt.EncryptHeaderV1) {
+		return value, nil
+	}
+
+	return m.opts.Encryptor.Decrypt(value)
+}
+
+func (m *Manager) expiration() []time.Duration {
+	if m.opts.Expiration > 0 {
+		return []time.Duration{m.opts.Expiration}
+	}
+
+	return nil
+}
+
+func cacheKey(key string) string {
+	return cachePrefix + key
+}
+
+// tenantCacheKey returns the cache key of the override of the tenant, or the
+// cache key of the global value when the tenant is empty
+func tenantCacheKey(tenant, key string) string {
+	if len(tenant) == 0 {
+		return cacheKey(key)
+	}
+	return cachePrefix + "tenant:" + tenant + ":" + key
+}
+
+// defaultValue returns the value of the item which is not persisted, the value
+// resolved by the resolver is returned first, then the value of the environment
+// and the default value of the item in the metadata
+func (m *Manager) defaultValue(item *config.Item) string {
+	if m.opts.Resolver != nil {
+		if cv, ok := m.opts.Resolver.Get(item.Name); ok {
+			return cv.Value
+		}
+	}
+
+	if len(item.EnvironmentKey) > 0 {
+		if value, ok := os.LookupEnv(item.EnvironmentKey); ok {
+			return value
+		}
+	}
+
+	return item.DefaultValue
+}
+
+// DryRun validates the values and returns the plan of the changes without
+// persisting them, the values are planned for the tenant in the ctx if any
+func (m *Manager) DryRun(ctx context.Context, cfgs map[string]interface{}) (*config.Plan, error) {
+	var lookupErr error
+	plan := config.NewPlan(cfgs, func(key string) (string, bool) {
+		value, err := m.get(ctx, key)
+		if err != nil {
+			lookupErr = err
+			return "", false
+		}
+
+		persisted, err := m.persisted(ctx, key)
+		if err != nil {
+			lookupErr = err
+		}
+		return value, persisted
+	})
+	if lookupErr != nil {
+		return nil, errors.Wrap(lookupErr, "failed to get the current configure values")
+	}
+
+	return plan, nil
+}
+
+// persisted returns true when the value of the key is persisted for the tenant
+// in the ctx, or globally when there is no tenant
+func (m *Manager) persisted(ctx context.Context, key string) (bool, error) {
+	var count int64
+	var err error
+	if tenant, ok := config.TenantFromContext(ctx); ok {
+		err = m.db.WithContext(ctx).Model(&TenantProperty{}).Where("tenant = ? AND k = ?", tenant, key).Count(&count).Error
+	} else {
+		err = m.db.WithContext(ctx).Model(&Property{}).Where("k = ?", key).Count(&count).Error
+	}
+	return count > 0, err
+}
@@ -0,0 +1,64 @@
+package db
+
+import (
+	"time"
+
+	"github.com/ling-server/core/config"
+)
+
+// Property is the configure value persisted in the database
+type Property struct {
+	ID    int64  `gorm:"primaryKey;column:id"`
+	Key   string `gorm:"column:k;uniqueIndex;size:64;not null"`
+	Value string `gorm:"column:v;type:text;not null"`
+}
+
+// TableName ...
+func (p *Property) TableName() string {
+	return "properties"
+}
+
+// TenantProperty is the configure value overridden for the tenant
+type TenantProperty struct {
+	ID     int64  `gorm:"primaryKey;column:id"`
+	Tenant string `gorm:"column:tenant;uniqueIndex:idx_tenant_properties_tenant_k,priority:1;size:255;not null"`
+	Key    string `gorm:"column:k;uniqueIndex:idx_tenant_properties_tenant_k,priority:2;size:64;not null"`
+	Value  string `gorm:"column:v;type:text;not null"`
+}
+
+// TableName ...
+func (p *TenantProperty) TableName() string {
+	return "tenant_properties"
+}
+
+// PropertyRevision is the audit record of the change of the configure value
+type PropertyRevision struct {
+	ID           int64     `gorm:"primaryKey;column:id"`
+	Key          string    `gorm:"column:k;index;size:64;not null"`
+	Tenant       string    `gorm:"column:tenant;index;size:255"`
+	OldValue     string    `gorm:"column:old_value;type:text"`
+	NewValue     string    `gorm:"column:new_value;type:text"`
+	Actor        string    `gorm:"column:actor;size:255"`
+	Reason       string    `gorm:"column:reason;type:text"`
+	CreationTime time.Time `gorm:"column:creation_time;index"`
+	Deleted      bool      `gorm:"column:deleted;not null;default:false"`
+}
+
+// TableName ...
+func (r *PropertyRevision) TableName() string {
+	return "property_revisions"
+}
+
+func (r *PropertyRevision) toRevision() *config.Revision {
+	return &config.Revision{
+		ID:       r.ID,
+		Key:      r.Key,
+		Tenant:   r.Tenant,
+		OldValue: r.OldValue,
+		NewValue: r.NewValue,
+		Actor:    r.Actor,
+		Reason:   r.Reason,
+		Time:     r.CreationTime,
+		Deleted:  r.Deleted,
+	}
+}
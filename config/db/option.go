@@ -0,0 +1,66 @@
+package db
+
+import (
+	"time"
+
+	"github.com/ling-server/core/cache"
+	"github.com/ling-server/core/config"
+	"github.com/ling-server/core/encrypt"
+)
+
+// Options for the database config manager
+type Options struct {
+	Cache      cache.Cache
+	Expiration time.Duration
+	Encryptor  encrypt.Encryptor
+	Resolver   *config.Resolver
+	Notifiers  []config.Notifier
+}
+
+type Option func(*Options)
+
+func newOptions(opt ...Option) Options {
+	opts := Options{}
+
+	for _, o := range opt {
+		o(&opts)
+	}
+
+	return opts
+}
+
+// Cache sets the cache used to cache the configure values read from database
+func Cache(c cache.Cache) Option {
+	return func(o *Options) {
+		o.Cache = c
+	}
+}
+
+// Expiration sets the expiration of the cached configure values
+func Expiration(d time.Duration) Option {
+	return func(o *Options) {
+		o.Expiration = d
+	}
+}
+
+// Encryptor sets the encryptor for the password values
+func Encryptor(e encrypt.Encryptor) Option {
+	return func(o *Options) {
+		o.Encryptor = e
+	}
+}
+
+// Resolver sets the resolver of the lower layers, the values resolved by it are
+// used when the configure values are not persisted, it is resolved by Load
+func Resolver(r *config.Resolver) Option {
+	return func(o *Options) {
+		o.Resolver = r
+	}
+}
+
+// Notifier appends the notifier which is notified when the values are saved
+func Notifier(n config.Notifier) Option {
+	return func(o *Options) {
+		o.Notifiers = append(o.Notifiers, n)
+	}
+}
@@ -0,0 +1,35 @@
+package feature
+
+import (
+	"context"
+
+	"github.com/ling-server/core/config"
+)
+
+// Target is the subject the feature flags are evaluated for
+type Target struct {
+	User   string
+	Tenant string
+	// Attributes the other attributes of the target, e.g. project
+	Attributes map[string]string
+}
+
+type targetKey struct{}
+
+// NewContext returns context with the target
+func NewContext(ctx context.Context, t *Target) context.Context {
+	return context.WithValue(ctx, targetKey{}, t)
+}
+
+// FromContext returns the target from context, the tenant of the config
+// manager is used when there is no tenant in the target
+func FromContext(ctx context.Context) *Target {
+	t := &Target{}
+	if target, ok := ctx.Value(targetKey{}).(*Target); ok && target != nil {
+		*t = *target
+	}
+	if len(t.Tenant) == 0 {
+		t.Tenant, _ = config.TenantFromContext(ctx)
+	}
+	return t
+}
@@ -0,0 +1,190 @@
+package feature
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+
+	"github.com/ling-server/core/config"
+	"github.com/ling-server/core/errors"
+	"github.com/ling-server/core/log"
+)
+
+const (
+	// Group the group of the configure items of the feature flags
+	Group = "feature"
+
+	keyPrefix = "feature_"
+)
+
+// Key returns the name of the configure item of the feature flag
+func Key(name string) string {
+	return keyPrefix + name
+}
+
+// Definition is the definition of the feature flag which is stored as the
+// json value of the configure item
+type Definition struct {
+	// Enabled the flag is disabled for everyone when it is false
+	Enabled bool `json:"enabled"`
+	// Users the flag is enabled for the users
+	Users []string `json:"users,omitempty"`
+	// Tenants the flag is enabled for the tenants
+	Tenants []string `json:"tenants,omitempty"`
+	// Attributes the flag is enabled for the targets which have one of the
+	// values of the attribute, e.g. {"project": ["library"]}
+	Attributes map[string][]string `json:"attributes,omitempty"`
+	// Percentage the percentage of the other targets the flag is enabled for,
+	// the flag is enabled for everyone when it is nil
+	Percentage *int `json:"percentage,omitempty"`
+}
+
+// FlagType is the type of the configure item of the feature flag
+type FlagType struct {
+}
+
+func (t *FlagType) Validate(str string) error {
+	_, err := parse(str)
+	return err
+}
+
+func (t *FlagType) Get(str string) (interface{}, error) {
+	return parse(str)
+}
+
+func parse(str string) (*Definition, error) {
+	def := &Definition{}
+	if err := json.Unmarshal([]byte(str), def); err != nil {
+		return nil, err
+	}
+	if def.Percentage != nil && (*def.Percentage < 0 || *def.Percentage > 100) {
+		return nil, fmt.Errorf("the percentage %d is not in the range [0, 100]", *def.Percentage)
+	}
+	return def, nil
+}
+
+// Register registers the feature flag as the editable user scope configure
+// item, the definition is the default value of the item
+func Register(name, description string, def *Definition) error {
+	value, err := json.Marshal(def)
+	if err != nil {
+		return err
+	}
+	if _, err := parse(string(value)); err != nil {
+		return errors.BadRequestError(err).WithMessage("invalid definition of the feature flag %s", name)
+	}
+
+	return config.Instance().RegisterItems(Group, config.Item{
+		Name:         Key(name),
+		Scope:        config.UserScope,
+		Group:        Group,
+		DefaultValue: string(value),
+		ItemType:     &FlagType{},
+		Editable:     true,
+		Description:  description,
+	})
+}
+
+// Evaluation is the result of the evaluation of the feature flag
+type Evaluation struct {
+	Flag    string
+	Enabled bool
+	// Reason why the flag is enabled or disabled
+	Reason string
+}
+
+// Evaluate evaluates the feature flag for the target in the context, the
+// definition is read from the config manager in the context, so the overrides
+// of the tenant of the target are respected
+func Evaluate(ctx context.Context, name string) (*Evaluation, error) {
+	target := FromContext(ctx)
+	if _, ok := config.TenantFromContext(ctx); !ok && len(target.Tenant) > 0 {
+		ctx = config.WithTenant(ctx, target.Tenant)
+	}
+
+	mgr := config.GetConfigManager(ctx)
+	if mgr == nil {
+		return nil, errors.UnknownError(nil).WithMessage("no config manager to evaluate the feature flag %s", name)
+	}
+	if _, ok := config.Instance().GetByName(Key(name)); !ok {
+		return nil, errors.NotFoundError(nil).WithMessage("the feature flag %s is not registered", name)
+	}
+
+	def, err := parse(mgr.Get(ctx, Key(name)).Value)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the definition of the feature flag %s", name)
+	}
+
+	e := evaluate(name, def, target)
+	log.G(ctx).Debugf("feature flag %s is evaluated to %t for user %q tenant %q: %s", name, e.Enabled, target.User, target.Tenant, e.Reason)
+	return e, nil
+}
+
+// Enabled returns whether the feature flag is enabled for the target in the
+// context, false is returned when the flag can not be evaluated
+func Enabled(ctx context.Context, name string) bool {
+	e, err := Evaluate(ctx, name)
+	if err != nil {
+		log.G(ctx).Errorf("failed to evaluate the feature flag %s, error: %v", name, err)
+		return false
+	}
+	return e.Enabled
+}
+
+func evaluate(name string, def *Definition, t *Target) *Evaluation {
+	e := &Evaluation{Flag: name}
+	switch {
+	case !def.Enabled:
+		e.Reason = "the flag is disabled"
+	case len(t.User) > 0 && contains(def.Users, t.User):
+		e.Enabled, e.Reason = true, "the user is targeted"
+	case len(t.Tenant) > 0 && contains(def.Tenants, t.Tenant):
+		e.Enabled, e.Reason = true, "the tenant is targeted"
+	case matchAttributes(def.Attributes, t.Attributes):
+		e.Enabled, e.Reason = true, "the attributes are targeted"
+	case def.Percentage == nil:
+		e.Enabled, e.Reason = true, "the flag is enabled"
+	default:
+		subject := t.User
+		if len(subject) == 0 {
+			subject = t.Tenant
+		}
+		switch {
+		case *def.Percentage >= 100:
+			e.Enabled, e.Reason = true, "the flag is rolled out to everyone"
+		case len(subject) == 0:
+			e.Reason = "the flag is rolled out partially and the target is anonymous"
+		default:
+			b := bucket(name, subject)
+			e.Enabled = b < *def.Percentage
+			e.Reason = fmt.Sprintf("the target is in bucket %d of the %d%% rollout", b, *def.Percentage)
+		}
+	}
+	return e
+}
+
+// bucket returns the stable bucket in [0, 100) of the subject for the flag
+func bucket(name, subject string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(name + ":" + subject))
+	return int(h.Sum32() % 100)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func matchAttributes(targeted map[string][]string, attrs map[string]string) bool {
+	for k, values := range targeted {
+		if v, ok := attrs[k]; ok && contains(values, v) {
+			return true
+		}
+	}
+	return false
+}
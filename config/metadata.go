@@ -1,6 +1,13 @@
 package config
 
-import "sync"
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ling-server/core/errors"
+)
 
 var metaDataOnce sync.Once
 var metaDataInstance *ConfigMetaData
@@ -23,6 +30,26 @@ type Item struct {
 	Editable bool `json:"editable,omitempty"`
 	// Description - Describle the usage of the configure item
 	Description string
+	// Constraints - the declarative constraints of the value, e.g. Min(1), Enum("a", "b")
+	Constraints []Constraint `json:"-"`
+	// SecretRef - the value can be the secret reference, e.g. file:///run/secrets/db, which is resolved by the secret provider,
+	// don't enable it for the items editable by the untrusted users as they can read any file or environment variable by the reference
+	SecretRef bool `json:"secret_ref,omitempty"`
+}
+
+// Validate validates the value against the type and the constraints of the item
+func (i *Item) Validate(value string) error {
+	if err := i.ItemType.Validate(value); err != nil {
+		return err
+	}
+
+	for _, c := range i.Constraints {
+		if err := c.Check(value); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 // Instance - Get Instance, make it singleton because there is only one copy of metadata in an env
@@ -34,40 +61,219 @@ func Instance() *ConfigMetaData {
 }
 
 func newConfigMetaData() *ConfigMetaData {
-	return &ConfigMetaData{metaMap: make(map[string]Item)}
+	return &ConfigMetaData{
+		metaMap:    make(map[string]Item),
+		owners:     make(map[string]string),
+		scopeIndex: make(map[string]map[string]struct{}),
+		groupIndex: make(map[string]map[string]struct{}),
+	}
 }
 
-// ConfigMetaData ...
+// ConfigMetaData is the registry of the configure items, it is safe for concurrent use
 type ConfigMetaData struct {
+	mu      sync.RWMutex
 	metaMap map[string]Item
+	rules   []Rule
+	// the owners of the items registered by RegisterItems
+	owners map[string]string
+	// the names of the items indexed by scope and group
+	scopeIndex map[string]map[string]struct{}
+	groupIndex map[string]map[string]struct{}
 }
 
 // initFromArray - Initial metadata from an array
 func (c *ConfigMetaData) InitFromArray(items []Item) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	c.metaMap = make(map[string]Item)
+	c.owners = make(map[string]string)
+	c.scopeIndex = make(map[string]map[string]struct{})
+	c.groupIndex = make(map[string]map[string]struct{})
 	for _, item := range items {
-		c.metaMap[item.Name] = item
+		c.register(item.Name, item)
 	}
 }
 
-// Registe - append the item to metadata
+// Registe - append the item to metadata, the item registered with the same name is replaced
 func (c *ConfigMetaData) Register(name string, item Item) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.register(name, item)
+}
+
+// RegisterItems - append the items of the owner to metadata, e.g. the package
+// which defines the items, the conflict error is returned and none of the
+// items is registered when any of the names has been registered
+func (c *ConfigMetaData) RegisterItems(owner string, items ...Item) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	conflicts := make([]string, 0)
+	names := make(map[string]struct{}, len(items))
+	for _, item := range items {
+		_, registered := c.metaMap[item.Name]
+		_, duplicated := names[item.Name]
+		if registered || duplicated {
+			conflict := item.Name
+			if o, ok := c.owners[item.Name]; ok {
+				conflict = fmt.Sprintf("%s (registered by %s)", item.Name, o)
+			}
+			conflicts = append(conflicts, conflict)
+		}
+		names[item.Name] = struct{}{}
+	}
+
+	if len(conflicts) > 0 {
+		return errors.ConflictError(nil).WithMessage("the configure items of %s are registered already: %s", owner, strings.Join(conflicts, ", "))
+	}
+
+	for _, item := range items {
+		c.register(item.Name, item)
+		c.owners[item.Name] = owner
+	}
+	return nil
+}
+
+// Unregister - remove the item from metadata, return false if it is not defined
+func (c *ConfigMetaData) Unregister(name string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	old, ok := c.metaMap[name]
+	if !ok {
+		return false
+	}
+
+	unindex(c.scopeIndex, old.Scope, name)
+	unindex(c.groupIndex, old.Group, name)
+	delete(c.metaMap, name)
+	delete(c.owners, name)
+	return true
+}
+
+func (c *ConfigMetaData) register(name string, item Item) {
+	if old, ok := c.metaMap[name]; ok {
+		unindex(c.scopeIndex, old.Scope, name)
+		unindex(c.groupIndex, old.Group, name)
+		delete(c.owners, name)
+	}
+
 	c.metaMap[name] = item
+	index(c.scopeIndex, item.Scope, name)
+	index(c.groupIndex, item.Group, name)
+}
+
+// GetByScope - Get the metadata in the scope, sorted by name
+func (c *ConfigMetaData) GetByScope(scope string) []Item {
+	return c.Query(scope, "")
+}
+
+// GetByGroup - Get the metadata in the group, sorted by name
+func (c *ConfigMetaData) GetByGroup(group string) []Item {
+	return c.Query("", group)
+}
+
+// Query - Get the metadata in the scope and group, sorted by name, the empty
+// scope or group matches any, e.g. Query(UserScope, "ldap")
+func (c *ConfigMetaData) Query(scope, group string) []Item {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var names map[string]struct{}
+	switch {
+	case len(scope) == 0 && len(group) == 0:
+		names = make(map[string]struct{}, len(c.metaMap))
+		for name := range c.metaMap {
+			names[name] = struct{}{}
+		}
+	case len(scope) == 0:
+		names = c.groupIndex[group]
+	case len(group) == 0:
+		names = c.scopeIndex[scope]
+	default:
+		names = make(map[string]struct{})
+		for name := range c.scopeIndex[scope] {
+			if _, ok := c.groupIndex[group][name]; ok {
+				names[name] = struct{}{}
+			}
+		}
+	}
+
+	metaDataList := make([]Item, 0, len(names))
+	for name := range names {
+		metaDataList = append(metaDataList, c.metaMap[name])
+	}
+	sort.Slice(metaDataList, func(i, j int) bool {
+		return metaDataList[i].Name < metaDataList[j].Name
+	})
+	return metaDataList
 }
 
 // GetByName - Get current metadata of current name, if not defined, return false in second params
 func (c *ConfigMetaData) GetByName(name string) (*Item, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
 	if item, ok := c.metaMap[name]; ok {
 		return &item, true
 	}
 	return nil, false
 }
 
-// GetAll - Get all metadata in current env
+// GetAll - Get all metadata in current env, sorted by name
 func (c *ConfigMetaData) GetAll() []Item {
-	metaDataList := make([]Item, 0)
-	for _, value := range c.metaMap {
-		metaDataList = append(metaDataList, value)
+	return c.Query("", "")
+}
+
+// RegisterRule - append the cross-field validation rule to metadata
+func (c *ConfigMetaData) RegisterRule(rule Rule) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.rules = append(c.rules, rule)
+}
+
+// GetRules - Get the cross-field validation rules which depend on any of the names
+func (c *ConfigMetaData) GetRules(names ...string) []Rule {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	rules := make([]Rule, 0)
+	for _, rule := range c.rules {
+		if dependsOn(rule, names) {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+func dependsOn(rule Rule, names []string) bool {
+	for _, key := range rule.Keys {
+		for _, name := range names {
+			if key == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func index(idx map[string]map[string]struct{}, key, name string) {
+	names, ok := idx[key]
+	if !ok {
+		names = make(map[string]struct{})
+		idx[key] = names
+	}
+	names[name] = struct{}{}
+}
+
+func unindex(idx map[string]map[string]struct{}, key, name string) {
+	if names, ok := idx[key]; ok {
+		delete(names, name)
+		if len(names) == 0 {
+			delete(idx, key)
+		}
 	}
-	return metaDataList
 }
@@ -0,0 +1,132 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"sort"
+)
+
+const (
+	// ActionAdded the item is not persisted and will be added
+	ActionAdded = "added"
+	// ActionChanged the persisted value of the item will be changed
+	ActionChanged = "changed"
+	// ActionUnchanged the value of the item is the same as the current one
+	ActionUnchanged = "unchanged"
+	// ActionRejected the value of the item is rejected
+	ActionRejected = "rejected"
+)
+
+// Change is the planned change of the configure item, the values of the
+// password items are redacted
+type Change struct {
+	Key      string `json:"key"`
+	Action   string `json:"action"`
+	OldValue string `json:"old_value,omitempty"`
+	NewValue string `json:"new_value,omitempty"`
+	// Reason why the value is rejected
+	Reason string `json:"reason,omitempty"`
+}
+
+// Plan is the result of the dry-run of UpdateConfig, the changes are sorted by key
+type Plan struct {
+	Changes []*Change `json:"changes"`
+}
+
+// Rejected returns true when any of the changes is rejected, the UpdateConfig
+// with the same values will fail
+func (p *Plan) Rejected() bool {
+	for _, c := range p.Changes {
+		if c.Action == ActionRejected {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter returns the changes of the action
+func (p *Plan) Filter(action string) []*Change {
+	changes := make([]*Change, 0)
+	for _, c := range p.Changes {
+		if c.Action == action {
+			changes = append(changes, c)
+		}
+	}
+	return changes
+}
+
+// DryRunner is implemented by the config manager which supports to dry-run
+// the UpdateConfig
+type DryRunner interface {
+	// DryRun validates the values and returns the plan of the changes without persisting them
+	DryRun(ctx context.Context, cfgs map[string]interface{}) (*Plan, error)
+}
+
+// DryRun dry-runs the UpdateConfig of the config manager of the ctx
+func DryRun(ctx context.Context, cfgs map[string]interface{}) (*Plan, error) {
+	r, ok := GetConfigManager(ctx).(DryRunner)
+	if !ok {
+		return nil, errors.New("the config manager does not support dry-run")
+	}
+	return r.DryRun(ctx, cfgs)
+}
+
+// NewPlan validates the values against the metadata and returns the plan of
+// the changes, the current function returns the current value of the item and
+// whether it is persisted
+func NewPlan(cfgs map[string]interface{}, current func(key string) (string, bool)) *Plan {
+	keys := make([]string, 0, len(cfgs))
+	for key := range cfgs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	changes := make(map[string]*Change, len(keys))
+	values := make(map[string]string, len(keys))
+	for _, key := range keys {
+		change := &Change{Key: key}
+		changes[key] = change
+
+		str, err := validateValue(key, cfgs[key])
+		if err != nil {
+			change.Action, change.Reason = ActionRejected, err.Error()
+			continue
+		}
+		change.NewValue = Redact(key, str)
+
+		item, _ := Instance().GetByName(key)
+		if !editable(item) {
+			change.Action, change.Reason = ActionRejected, "the configure item is not editable"
+			continue
+		}
+
+		values[key] = str
+		oldValue, persisted := current(key)
+		change.OldValue = Redact(key, oldValue)
+		switch {
+		case !persisted:
+			change.Action = ActionAdded
+		case oldValue == str:
+			change.Action = ActionUnchanged
+		default:
+			change.Action = ActionChanged
+		}
+	}
+
+	for _, v := range checkRules(keys, values, func(key string) string {
+		value, _ := current(key)
+		return value
+	}) {
+		for _, key := range v.rule.Keys {
+			if change, ok := changes[key]; ok && change.Action != ActionRejected {
+				change.Action, change.Reason = ActionRejected, v.err.Error()
+			}
+		}
+	}
+
+	plan := &Plan{Changes: make([]*Change, 0, len(keys))}
+	for _, key := range keys {
+		plan.Changes = append(plan.Changes, changes[key])
+	}
+	return plan
+}
@@ -0,0 +1,114 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/ling-server/core/cache"
+	rediscache "github.com/ling-server/core/cache/redis"
+	"github.com/ling-server/core/config"
+	"github.com/ling-server/core/errors"
+	"github.com/ling-server/core/log"
+)
+
+// DefaultChannel the default redis channel of the config change events
+const DefaultChannel = "config:events"
+
+var _ config.Watcher = (*PubSub)(nil)
+var _ config.Notifier = (*PubSub)(nil)
+
+// PubSub pushes the config change events to all the replicas by redis pub/sub,
+// it publishes the events when it is notified and delivers the events received
+// from the channel to the watchers
+type PubSub struct {
+	client      *redis.Client
+	channel     string
+	broadcaster *config.Broadcaster
+	mu          sync.Mutex
+	pubsub      *redis.PubSub
+}
+
+// New returns the PubSub on the channel of the redis client
+func New(client *redis.Client, channel string) *PubSub {
+	if len(channel) == 0 {
+		channel = DefaultChannel
+	}
+
+	return &PubSub{
+		client:      client,
+		channel:     channel,
+		broadcaster: config.NewBroadcaster(),
+	}
+}
+
+// NewFromURL returns the PubSub on the channel of the redis url, the url is in
+// the same format as the one of the redis cache, e.g. redis://localhost:6379/0
+// or redis+sentinel://s1:26379,s2:26379/mymaster/0
+func NewFromURL(addr, channel string) (*PubSub, error) {
+	c, err := rediscache.New(cache.Options{Address: addr})
+	if err != nil {
+		return nil, err
+	}
+
+	return New(c.(*rediscache.Cache).Client, channel), nil
+}
+
+// Notify publishes the events to the channel
+func (p *PubSub) Notify(ctx context.Context, events ...*config.Event) error {
+	for _, e := range events {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+
+		if err := p.client.Publish(ctx, p.channel, data).Err(); err != nil {
+			return errors.Wrapf(err, "failed to publish the change event of %s", e.Key)
+		}
+	}
+
+	return nil
+}
+
+// Watch returns the events of the keys received from the channel, the
+// subscription of the channel is started at the first call
+func (p *PubSub) Watch(ctx context.Context, keys ...string) (<-chan *config.Event, error) {
+	p.mu.Lock()
+	if p.pubsub == nil {
+		p.pubsub = p.client.Subscribe(context.Background(), p.channel)
+		go p.receive(p.pubsub.Channel())
+	}
+	p.mu.Unlock()
+
+	return p.broadcaster.Watch(ctx, keys...)
+}
+
+// Close closes the subscription of the channel
+func (p *PubSub) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.pubsub == nil {
+		return nil
+	}
+
+	err := p.pubsub.Close()
+	p.pubsub = nil
+	return err
+}
+
+func (p *PubSub) receive(messages <-chan *redis.Message) {
+	for msg := range messages {
+		e := &config.Event{}
+		if err := json.Unmarshal([]byte(msg.Payload), e); err != nil {
+			log.Errorf("failed to decode the config change event from %s, error: %v", p.channel, err)
+			continue
+		}
+
+		if err := p.broadcaster.Notify(context.Background(), e); err != nil {
+			log.Errorf("failed to deliver the change event of %s, error: %v", e.Key, err)
+		}
+	}
+}
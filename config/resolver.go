@@ -0,0 +1,130 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/ling-server/core/log"
+)
+
+// Resolved is the effective configure value and the layer it came from
+type Resolved struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	// Layer the name of the source layer which provides the effective value
+	Layer string `json:"layer"`
+	// Overridden the names of the lower layers whose values are overridden
+	Overridden []string `json:"overridden,omitempty"`
+}
+
+// String returns the description of the resolved value, the password is redacted
+func (r *Resolved) String() string {
+	return fmt.Sprintf("%s=%q (from %s, overrides %v)", r.Name, Redact(r.Name, r.Value), r.Layer, r.Overridden)
+}
+
+// Resolver resolves the effective configure values from the layered sources,
+// the value provided by the later source overrides the one provided by the
+// former, e.g. NewResolver(NewDefaultSource(), NewFileSource(path),
+// NewEnvironmentSource(), store) resolves the values in the precedence of
+// store > environment > file > default.
+type Resolver struct {
+	sources []Source
+	mu      sync.RWMutex
+	values  map[string]*Resolved
+}
+
+// NewResolver returns a resolver of the sources, the sources are in the
+// ascending order of precedence
+func NewResolver(sources ...Source) *Resolver {
+	return &Resolver{
+		sources: sources,
+		values:  map[string]*Resolved{},
+	}
+}
+
+// Resolve loads all the sources and merges the values in precedence order,
+// the values which are not defined in the metadata or invalid are ignored
+func (r *Resolver) Resolve(ctx context.Context) (map[string]*Resolved, error) {
+	values := map[string]*Resolved{}
+	for _, s := range r.sources {
+		layer, err := s.Load(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the %s layer: %v", s.Name(), err)
+		}
+
+		for name, value := range layer {
+			item, ok := Instance().GetByName(name)
+			if !ok {
+				log.Warningf("the configure item %s in the %s layer is not defined, ignored", name, s.Name())
+				continue
+			}
+			if err := item.Validate(value); err != nil {
+				log.Warningf("the value of the configure item %s in the %s layer is invalid, ignored, error: %v", name, s.Name(), err)
+				continue
+			}
+
+			resolved := &Resolved{Name: name, Value: value, Layer: s.Name()}
+			if prev, ok := values[name]; ok {
+				resolved.Overridden = append(prev.Overridden, prev.Layer)
+			}
+			values[name] = resolved
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.values = values
+	return r.copy(), nil
+}
+
+// Get returns the effective value resolved by the last Resolve
+func (r *Resolver) Get(name string) (*ConfigureValue, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	resolved, ok := r.values[name]
+	if !ok {
+		return nil, false
+	}
+	return &ConfigureValue{Name: resolved.Name, Value: resolved.Value}, true
+}
+
+// Origin returns the layer which the effective value of the name came from
+func (r *Resolver) Origin(name string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	resolved, ok := r.values[name]
+	if !ok {
+		return "", false
+	}
+	return resolved.Layer, true
+}
+
+// Explain returns the resolved values sorted by name for debugging
+func (r *Resolver) Explain() []*Resolved {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*Resolved, 0, len(r.values))
+	for _, resolved := range r.copy() {
+		result = append(result, resolved)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+func (r *Resolver) copy() map[string]*Resolved {
+	result := make(map[string]*Resolved, len(r.values))
+	for name, resolved := range r.values {
+		c := *resolved
+		c.Overridden = append([]string(nil), resolved.Overridden...)
+		result[name] = &c
+	}
+	return result
+}
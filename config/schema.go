@@ -0,0 +1,201 @@
+package config
+
+import (
+	"encoding/json"
+	"math"
+)
+
+// JSONSchemaDraft the version of the generated JSON Schema
+const JSONSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is the JSON Schema of the configure values, it is also compatible
+// with the schema object of OpenAPI 3, the x- prefixed fields are extensions
+// which carry the metadata of the configure item
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Default              interface{}        `json:"default,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	ReadOnly             bool               `json:"readOnly,omitempty"`
+	WriteOnly            bool               `json:"writeOnly,omitempty"`
+	Editable             *bool              `json:"x-editable,omitempty"`
+	Scope                string             `json:"x-scope,omitempty"`
+	Group                string             `json:"x-group,omitempty"`
+	EnvironmentKey       string             `json:"x-environment-key,omitempty"`
+}
+
+// SchemaProvider is implemented by the custom Type to describe its values in
+// the JSON Schema, the Type is described as string if it doesn't implement it
+type SchemaProvider interface {
+	Schema() *Schema
+}
+
+// JSONSchema returns the JSON Schema of the values of the items in metadata,
+// the items are the properties of the object
+func (c *ConfigMetaData) JSONSchema() *Schema {
+	s := c.objectSchema()
+	s.Schema = JSONSchemaDraft
+	s.Title = "Configurations"
+	return s
+}
+
+// OpenAPI returns the OpenAPI 3 components which contains the schema of the
+// values of the items in metadata with the name
+func (c *ConfigMetaData) OpenAPI(name string) map[string]interface{} {
+	return map[string]interface{}{
+		"components": map[string]interface{}{
+			"schemas": map[string]*Schema{
+				name: c.objectSchema(),
+			},
+		},
+	}
+}
+
+func (c *ConfigMetaData) objectSchema() *Schema {
+	s := &Schema{
+		Type:       "object",
+		Properties: map[string]*Schema{},
+	}
+
+	for _, item := range c.GetAll() {
+		s.Properties[item.Name] = item.Schema()
+	}
+	return s
+}
+
+// Schema returns the JSON Schema of the value of the item
+func (i *Item) Schema() *Schema {
+	s := typeSchema(i.ItemType)
+	for _, c := range i.Constraints {
+		constraintSchema(s, i, c)
+	}
+
+	editable := i.Editable
+	s.Description = i.Description
+	s.Editable = &editable
+	s.ReadOnly = !i.Editable
+	s.Scope = i.Scope
+	s.Group = i.Group
+	s.EnvironmentKey = i.EnvironmentKey
+
+	// the default value of the password is not exposed
+	if _, ok := i.ItemType.(*PasswordType); !ok && len(i.DefaultValue) > 0 {
+		s.Default = defaultSchemaValue(s, i)
+	}
+
+	return s
+}
+
+// defaultSchemaValue returns the default value matches the type of the schema,
+// e.g. the raw string of the byte size and the string list of the CIDRs
+func defaultSchemaValue(s *Schema, i *Item) interface{} {
+	return schemaValue(s, i, i.DefaultValue)
+}
+
+// schemaValue returns the value matches the type of the schema
+func schemaValue(s *Schema, i *Item, value string) interface{} {
+	switch s.Type {
+	case "string":
+		return value
+	case "array":
+		if list, err := parseStringList(value); err == nil {
+			return list
+		}
+		return value
+	default:
+		if val, err := i.ItemType.Get(value); err == nil && isJSONValue(val) {
+			return val
+		}
+		return value
+	}
+}
+
+func typeSchema(t Type) *Schema {
+	switch t.(type) {
+	case *NonEmptyStringType:
+		minLength := 1
+		return &Schema{Type: "string", MinLength: &minLength}
+	case *PasswordType:
+		return &Schema{Type: "string", Format: "password", WriteOnly: true}
+	case *PortType:
+		return &Schema{Type: "integer", Format: "int32", Minimum: float(0), Maximum: float(65535)}
+	case *IntType:
+		return &Schema{Type: "integer", Format: "int32"}
+	case *QuotaType:
+		return &Schema{Type: "integer", Format: "int64", Minimum: float(-1)}
+	case *Int64Type:
+		return &Schema{Type: "integer", Format: "int64"}
+	case *Float64Type:
+		return &Schema{Type: "number", Format: "double"}
+	case *BoolType:
+		return &Schema{Type: "boolean"}
+	case *MapType:
+		return &Schema{Type: "object"}
+	case *StringToStringMapType:
+		return &Schema{Type: "object", AdditionalProperties: &Schema{Type: "string"}}
+	case *DurationType:
+		return &Schema{Type: "string", Format: "duration"}
+	case *URLType:
+		return &Schema{Type: "string", Format: "uri"}
+	case *StringListType:
+		return &Schema{Type: "array", Items: &Schema{Type: "string"}}
+	case *CIDRType:
+		return &Schema{Type: "array", Items: &Schema{Type: "string", Format: "cidr"}}
+	case *ByteSizeType:
+		return &Schema{Type: "string", Format: "byte-size", Pattern: `^\s*\d+(\.\d+)?\s*(?i:[kmgt]i?b?|b)?\s*$`}
+	case SchemaProvider:
+		if s := t.(SchemaProvider).Schema(); s != nil {
+			return s
+		}
+	}
+
+	return &Schema{Type: "string"}
+}
+
+func constraintSchema(s *Schema, i *Item, c Constraint) {
+	switch c := c.(type) {
+	case *MinConstraint:
+		s.Minimum = float(c.Min)
+	case *MaxConstraint:
+		s.Maximum = float(c.Max)
+	case *PatternConstraint:
+		s.Pattern = c.Pattern.String()
+	case *EnumConstraint:
+		s.Enum = make([]interface{}, 0, len(c.Values))
+		for _, v := range c.Values {
+			s.Enum = append(s.Enum, schemaValue(s, i, v))
+		}
+	case *URLConstraint:
+		s.Format = "uri"
+	case *DurationConstraint:
+		s.Format = "duration"
+	}
+}
+
+func float(f float64) *float64 {
+	return &f
+}
+
+// isJSONValue returns true when the value can be encoded in JSON as it is
+func isJSONValue(val interface{}) bool {
+	if f, ok := val.(float64); ok && (math.IsInf(f, 0) || math.IsNaN(f)) {
+		return false
+	}
+	switch val.(type) {
+	case string, bool, int, int64, float64, []string, map[string]string, map[string]interface{}:
+		_, err := json.Marshal(val)
+		return err == nil
+	default:
+		return false
+	}
+}
@@ -0,0 +1,200 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ling-server/core/log"
+)
+
+// SecretProvider resolves the secret referenced by the uri in the configure
+// value, e.g. file:///run/secrets/db or env://DB_PASS
+type SecretProvider interface {
+	// Resolve returns the secret referenced by the uri
+	Resolve(ctx context.Context, ref *url.URL) (string, error)
+}
+
+// DefaultSecretCacheTTL the time the secrets resolved by the registered providers are cached
+const DefaultSecretCacheTTL = time.Minute
+
+var (
+	secretProvidersMU sync.RWMutex
+	secretProviders   = map[string]SecretProvider{
+		"file": NewFileSecretProvider(),
+		"env":  NewCachedSecretProvider(&EnvSecretProvider{}, DefaultSecretCacheTTL),
+	}
+)
+
+// RegisterSecretProvider registers the provider of the scheme, the values in
+// the form of scheme://... are resolved by the provider, the secrets are
+// cached for DefaultSecretCacheTTL unless the provider caches them itself,
+// i.e. the FileSecretProvider and the one returned by NewCachedSecretProvider
+func RegisterSecretProvider(scheme string, provider SecretProvider) {
+	secretProvidersMU.Lock()
+	defer secretProvidersMU.Unlock()
+
+	switch provider.(type) {
+	case nil:
+		log.Error("Register secret provider is nil")
+	case *FileSecretProvider, *CachedSecretProvider:
+	default:
+		provider = NewCachedSecretProvider(provider, DefaultSecretCacheTTL)
+	}
+	secretProviders[strings.ToLower(scheme)] = provider
+}
+
+// ResolveSecret returns the secret referenced by the value, the false is
+// returned when the value is not a reference of any registered provider
+func ResolveSecret(ctx context.Context, value string) (string, bool, error) {
+	i := strings.Index(value, "://")
+	if i <= 0 {
+		return value, false, nil
+	}
+
+	secretProvidersMU.RLock()
+	provider, ok := secretProviders[strings.ToLower(value[:i])]
+	secretProvidersMU.RUnlock()
+	if !ok || provider == nil {
+		return value, false, nil
+	}
+
+	ref, err := url.Parse(value)
+	if err != nil {
+		return "", true, fmt.Errorf("invalid secret reference: %v", err)
+	}
+
+	secret, err := provider.Resolve(ctx, ref)
+	return secret, true, err
+}
+
+// dereference returns the secret when the SecretRef of the item is enabled and
+// the value is a secret reference, the empty string is returned when the secret
+// can't be resolved
+func dereference(item *Item, value string) string {
+	if !item.SecretRef {
+		return value
+	}
+
+	secret, isRef, err := ResolveSecret(context.Background(), value)
+	if !isRef {
+		return value
+	}
+	if err != nil {
+		log.Errorf("failed to resolve the secret of the configure item %s, error: %v", item.Name, err)
+		return ""
+	}
+	return secret
+}
+
+type cachedSecret struct {
+	value   string
+	expires time.Time
+}
+
+// CachedSecretProvider caches the secrets resolved by the provider in the ttl,
+// the failures are not cached
+type CachedSecretProvider struct {
+	provider SecretProvider
+	ttl      time.Duration
+
+	mu    sync.Mutex
+	cache map[string]*cachedSecret
+}
+
+// NewCachedSecretProvider returns the provider caches the secrets resolved by the provider
+func NewCachedSecretProvider(provider SecretProvider, ttl time.Duration) *CachedSecretProvider {
+	return &CachedSecretProvider{
+		provider: provider,
+		ttl:      ttl,
+		cache:    map[string]*cachedSecret{},
+	}
+}
+
+// Resolve ...
+func (p *CachedSecretProvider) Resolve(ctx context.Context, ref *url.URL) (string, error) {
+	key := ref.String()
+
+	p.mu.Lock()
+	s, ok := p.cache[key]
+	p.mu.Unlock()
+	if ok && time.Now().Before(s.expires) {
+		return s.value, nil
+	}
+
+	value, err := p.provider.Resolve(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+
+	p.mu.Lock()
+	p.cache[key] = &cachedSecret{value: value, expires: time.Now().Add(p.ttl)}
+	p.mu.Unlock()
+	return value, nil
+}
+
+// EnvSecretProvider resolves the secret from the environment variable, e.g. env://DB_PASS
+type EnvSecretProvider struct{}
+
+// Resolve ...
+func (p *EnvSecretProvider) Resolve(ctx context.Context, ref *url.URL) (string, error) {
+	key := ref.Host + ref.Path
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", key)
+	}
+	return value, nil
+}
+
+type fileSecret struct {
+	value   string
+	modTime time.Time
+	size    int64
+}
+
+// FileSecretProvider resolves the secret from the file, e.g. file:///run/secrets/db,
+// the trailing line break of the content is trimmed, the content is cached and
+// read again when the file changes
+type FileSecretProvider struct {
+	mu    sync.Mutex
+	cache map[string]*fileSecret
+}
+
+// NewFileSecretProvider returns an instance of the FileSecretProvider
+func NewFileSecretProvider() *FileSecretProvider {
+	return &FileSecretProvider{cache: map[string]*fileSecret{}}
+}
+
+// Resolve ...
+func (p *FileSecretProvider) Resolve(ctx context.Context, ref *url.URL) (string, error) {
+	path := ref.Path
+	if len(ref.Host) > 0 {
+		// the relative path, e.g. file://secrets/db
+		path = ref.Host + ref.Path
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if s, ok := p.cache[path]; ok && s.modTime.Equal(info.ModTime()) && s.size == info.Size() {
+		return s.value, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	value := strings.TrimRight(string(data), "\r\n")
+	p.cache[path] = &fileSecret{value: value, modTime: info.ModTime(), size: info.Size()}
+	return value, nil
+}
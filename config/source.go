@@ -0,0 +1,115 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// DefaultLayer the layer of the default values in the metadata
+	DefaultLayer = "default"
+	// FileLayer the layer of the values in the config file
+	FileLayer = "file"
+	// EnvironmentLayer the layer of the values in the environment variables
+	EnvironmentLayer = "environment"
+	// StoreLayer the layer of the values in the persistent store
+	StoreLayer = "store"
+)
+
+// Source provides the raw configure values of a layer
+type Source interface {
+	// Name returns the name of the layer
+	Name() string
+	// Load returns the values provided by the layer, the key is the name of the configure item
+	Load(ctx context.Context) (map[string]string, error)
+}
+
+type source struct {
+	name string
+	load func(ctx context.Context) (map[string]string, error)
+}
+
+func (s *source) Name() string {
+	return s.name
+}
+
+func (s *source) Load(ctx context.Context) (map[string]string, error) {
+	return s.load(ctx)
+}
+
+// NewSource returns a source of the layer which loads the values by the load function
+func NewSource(name string, load func(ctx context.Context) (map[string]string, error)) Source {
+	return &source{name: name, load: load}
+}
+
+// NewDefaultSource returns the source of the default values in the metadata,
+// the items without default value are not in the source
+func NewDefaultSource() Source {
+	return NewSource(DefaultLayer, func(ctx context.Context) (map[string]string, error) {
+		values := map[string]string{}
+		for _, item := range Instance().GetAll() {
+			if len(item.DefaultValue) == 0 {
+				continue
+			}
+			values[item.Name] = item.DefaultValue
+		}
+		return values, nil
+	})
+}
+
+// NewEnvironmentSource returns the source of the environment variables, the
+// variables are looked up by the EnvironmentKey of the items in the metadata
+func NewEnvironmentSource() Source {
+	return NewSource(EnvironmentLayer, func(ctx context.Context) (map[string]string, error) {
+		values := map[string]string{}
+		for _, item := range Instance().GetAll() {
+			if len(item.EnvironmentKey) == 0 {
+				continue
+			}
+			if value, ok := os.LookupEnv(item.EnvironmentKey); ok {
+				values[item.Name] = value
+			}
+		}
+		return values, nil
+	})
+}
+
+// NewFileSource returns the source of the config file, the file is decoded as
+// json when its extension is .json, and as yaml when it is .yaml or .yml
+func NewFileSource(path string) Source {
+	return NewSource(FileLayer, func(ctx context.Context) (map[string]string, error) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		raw := map[string]interface{}{}
+		switch ext := strings.ToLower(filepath.Ext(path)); ext {
+		case ".json":
+			err = json.Unmarshal(data, &raw)
+		case ".yaml", ".yml":
+			err = yaml.Unmarshal(data, &raw)
+		default:
+			return nil, fmt.Errorf("unsupported config file format: %s", ext)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode config file %s: %v", path, err)
+		}
+
+		values := make(map[string]string, len(raw))
+		for key, value := range raw {
+			str, err := StringValue(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value of %s in config file %s: %v", key, path, err)
+			}
+			values[key] = str
+		}
+		return values, nil
+	})
+}
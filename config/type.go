@@ -4,27 +4,31 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"net"
+	"net/url"
 	"strconv"
 	"strings"
+	"time"
 )
 
-// Type - Use this interface to define and encapsulate the behavior of validation and transformation
+// Type - Use this interface to define and encapsulate the behavior of validation and transformation,
+// implement it to add the custom type of the configure items
 type Type interface {
-	// validate the configure value
-	validate(str string) error
-	// get the real type of current value, if it is int, return int, if it is string return string etc.
-	get(str string) (interface{}, error)
+	// Validate the configure value
+	Validate(str string) error
+	// Get the real type of current value, if it is int, return int, if it is string return string etc.
+	Get(str string) (interface{}, error)
 }
 
 // StringType ...
 type StringType struct {
 }
 
-func (t *StringType) validate(str string) error {
+func (t *StringType) Validate(str string) error {
 	return nil
 }
 
-func (t *StringType) get(str string) (interface{}, error) {
+func (t *StringType) Get(str string) (interface{}, error) {
 	return str, nil
 }
 
@@ -33,7 +37,7 @@ type NonEmptyStringType struct {
 	StringType
 }
 
-func (t *NonEmptyStringType) validate(str string) error {
+func (t *NonEmptyStringType) Validate(str string) error {
 	if len(strings.TrimSpace(str)) == 0 {
 		return ErrorStringValueIsEmpty
 	}
@@ -44,12 +48,12 @@ func (t *NonEmptyStringType) validate(str string) error {
 type IntType struct {
 }
 
-func (t *IntType) validate(str string) error {
+func (t *IntType) Validate(str string) error {
 	_, err := parseInt(str)
 	return err
 }
 
-func (t *IntType) get(str string) (interface{}, error) {
+func (t *IntType) Get(str string) (interface{}, error) {
 	return parseInt(str)
 }
 
@@ -58,7 +62,7 @@ type PortType struct {
 	IntType
 }
 
-func (t *PortType) validate(str string) error {
+func (t *PortType) Validate(str string) error {
 	val, err := strconv.Atoi(str)
 	if err != nil {
 		return err
@@ -78,23 +82,23 @@ func (t *PortType) validate(str string) error {
 type Int64Type struct {
 }
 
-func (t *Int64Type) validate(str string) error {
+func (t *Int64Type) Validate(str string) error {
 	_, err := parseInt64(str)
 	return err
 }
 
-func (t *Int64Type) get(str string) (interface{}, error) {
+func (t *Int64Type) Get(str string) (interface{}, error) {
 	return parseInt64(str)
 }
 
 type Float64Type struct{}
 
-func (f *Float64Type) validate(str string) error {
+func (f *Float64Type) Validate(str string) error {
 	_, err := parseFloat64(str)
 	return err
 }
 
-func (f *Float64Type) get(str string) (interface{}, error) {
+func (f *Float64Type) Get(str string) (interface{}, error) {
 	return parseFloat64(str)
 }
 
@@ -102,12 +106,12 @@ func (f *Float64Type) get(str string) (interface{}, error) {
 type BoolType struct {
 }
 
-func (t *BoolType) validate(str string) error {
+func (t *BoolType) Validate(str string) error {
 	_, err := strconv.ParseBool(str)
 	return err
 }
 
-func (t *BoolType) get(str string) (interface{}, error) {
+func (t *BoolType) Get(str string) (interface{}, error) {
 	return strconv.ParseBool(str)
 }
 
@@ -115,11 +119,11 @@ func (t *BoolType) get(str string) (interface{}, error) {
 type PasswordType struct {
 }
 
-func (t *PasswordType) validate(str string) error {
+func (t *PasswordType) Validate(str string) error {
 	return nil
 }
 
-func (t *PasswordType) get(str string) (interface{}, error) {
+func (t *PasswordType) Get(str string) (interface{}, error) {
 	return str, nil
 }
 
@@ -127,13 +131,13 @@ func (t *PasswordType) get(str string) (interface{}, error) {
 type MapType struct {
 }
 
-func (t *MapType) validate(str string) error {
+func (t *MapType) Validate(str string) error {
 	result := map[string]interface{}{}
 	err := json.Unmarshal([]byte(str), &result)
 	return err
 }
 
-func (t *MapType) get(str string) (interface{}, error) {
+func (t *MapType) Get(str string) (interface{}, error) {
 	result := map[string]interface{}{}
 	err := json.Unmarshal([]byte(str), &result)
 	return result, err
@@ -143,13 +147,13 @@ func (t *MapType) get(str string) (interface{}, error) {
 type StringToStringMapType struct {
 }
 
-func (t *StringToStringMapType) validate(str string) error {
+func (t *StringToStringMapType) Validate(str string) error {
 	result := map[string]string{}
 	err := json.Unmarshal([]byte(str), &result)
 	return err
 }
 
-func (t *StringToStringMapType) get(str string) (interface{}, error) {
+func (t *StringToStringMapType) Get(str string) (interface{}, error) {
 	result := map[string]string{}
 	err := json.Unmarshal([]byte(str), &result)
 	return result, err
@@ -160,7 +164,7 @@ type QuotaType struct {
 	Int64Type
 }
 
-func (t *QuotaType) validate(str string) error {
+func (t *QuotaType) Validate(str string) error {
 	val, err := parseInt64(str)
 	if err != nil {
 		return err
@@ -173,6 +177,72 @@ func (t *QuotaType) validate(str string) error {
 	return nil
 }
 
+// DurationType ...
+type DurationType struct {
+}
+
+func (t *DurationType) Validate(str string) error {
+	_, err := time.ParseDuration(str)
+	return err
+}
+
+func (t *DurationType) Get(str string) (interface{}, error) {
+	return time.ParseDuration(str)
+}
+
+// URLType ...
+type URLType struct {
+}
+
+func (t *URLType) Validate(str string) error {
+	_, err := parseURL(str)
+	return err
+}
+
+func (t *URLType) Get(str string) (interface{}, error) {
+	return parseURL(str)
+}
+
+// StringListType the comma separated list, e.g. "a, b, c", or the json array
+type StringListType struct {
+}
+
+func (t *StringListType) Validate(str string) error {
+	_, err := parseStringList(str)
+	return err
+}
+
+func (t *StringListType) Get(str string) (interface{}, error) {
+	return parseStringList(str)
+}
+
+// CIDRType the comma separated list of the CIDRs, e.g. "10.0.0.0/8, 192.168.1.1",
+// the single ip is treated as the CIDR only contains itself
+type CIDRType struct {
+}
+
+func (t *CIDRType) Validate(str string) error {
+	_, err := parseCIDRs(str)
+	return err
+}
+
+func (t *CIDRType) Get(str string) (interface{}, error) {
+	return parseCIDRs(str)
+}
+
+// ByteSizeType the size of bytes with the optional unit, e.g. 1024, 10KB, 512MiB
+type ByteSizeType struct {
+}
+
+func (t *ByteSizeType) Validate(str string) error {
+	_, err := parseByteSize(str)
+	return err
+}
+
+func (t *ByteSizeType) Get(str string) (interface{}, error) {
+	return parseByteSize(str)
+}
+
 // parseInt64 returns int64 from string which support scientific notation
 func parseInt64(str string) (int64, error) {
 	val, err := strconv.ParseInt(str, 10, 64)
@@ -210,3 +280,106 @@ func parseFloat64(str string) (float64, error) {
 
 	return 0, fmt.Errorf("invalid float64 string: %s", str)
 }
+
+func parseURL(str string) (*url.URL, error) {
+	u, err := url.Parse(str)
+	if err != nil || len(u.Scheme) == 0 || len(u.Host) == 0 {
+		return nil, fmt.Errorf("invalid url string: %s", str)
+	}
+
+	return u, nil
+}
+
+func parseStringList(str string) ([]string, error) {
+	result := make([]string, 0)
+	if strings.HasPrefix(strings.TrimSpace(str), "[") {
+		if err := json.Unmarshal([]byte(str), &result); err != nil {
+			return nil, fmt.Errorf("invalid string list: %s", str)
+		}
+		return result, nil
+	}
+
+	for _, s := range strings.Split(str, ",") {
+		if s = strings.TrimSpace(s); len(s) > 0 {
+			result = append(result, s)
+		}
+	}
+	return result, nil
+}
+
+func parseCIDRs(str string) ([]*net.IPNet, error) {
+	list, err := parseStringList(str)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*net.IPNet, 0, len(list))
+	for _, s := range list {
+		if !strings.Contains(s, "/") {
+			ip := net.ParseIP(s)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid ip: %s", s)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			result = append(result, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, ipNet, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR: %s", s)
+		}
+		result = append(result, ipNet)
+	}
+	return result, nil
+}
+
+var byteUnits = map[string]float64{
+	"":    1,
+	"b":   1,
+	"k":   1000,
+	"kb":  1000,
+	"kib": 1 << 10,
+	"m":   1000 * 1000,
+	"mb":  1000 * 1000,
+	"mib": 1 << 20,
+	"g":   1000 * 1000 * 1000,
+	"gb":  1000 * 1000 * 1000,
+	"gib": 1 << 30,
+	"t":   1000 * 1000 * 1000 * 1000,
+	"tb":  1000 * 1000 * 1000 * 1000,
+	"tib": 1 << 40,
+}
+
+// parseByteSize returns the bytes of the size, the units are case insensitive,
+// KB, MB, GB and TB are in the power of 1000, KiB, MiB, GiB and TiB are in the
+// power of 1024
+func parseByteSize(str string) (int64, error) {
+	s := strings.TrimSpace(str)
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i < 0 {
+		i = len(s)
+	}
+
+	unit, ok := byteUnits[strings.ToLower(strings.TrimSpace(s[i:]))]
+	if !ok {
+		return 0, fmt.Errorf("invalid byte size string: %s", str)
+	}
+
+	// float64(math.MaxInt64) rounds up to 2^63 which overflows the int64
+	val, err := strconv.ParseFloat(s[:i], 64)
+	if err != nil || val*unit >= math.MaxInt64 {
+		return 0, fmt.Errorf("invalid byte size string: %s", str)
+	}
+
+	size := int64(val * unit)
+	if size < 0 {
+		return 0, fmt.Errorf("invalid byte size string: %s", str)
+	}
+	return size, nil
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// StringValue returns the string form of the value which is used to store
+// the configure value, the map and slice values are encoded as json
+func StringValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), nil
+	case map[string]interface{}, map[string]string, []interface{}, []string:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	default:
+		return fmt.Sprintf("%v", v), nil
+	}
+}
+
+// Redact returns the redacted value when the configure item is a password
+func Redact(name, value string) string {
+	if len(value) == 0 {
+		return value
+	}
+	if item, ok := Instance().GetByName(name); ok {
+		if _, isPassword := item.ItemType.(*PasswordType); isPassword {
+			return "xxxxx"
+		}
+	}
+	return value
+}
@@ -0,0 +1,114 @@
+package config
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/ling-server/core/errors"
+)
+
+// ValidateValues validates the configure values against the metadata, the current
+// function returns the current value of the item which is used to check the
+// cross-field rules, all the violations are returned as errors.Errors with the
+// BadRequestCode, nil is returned when there is no violation
+func ValidateValues(cfgs map[string]interface{}, current func(key string) string) error {
+	keys := make([]string, 0, len(cfgs))
+	for key := range cfgs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var errs errors.Errors
+	values := make(map[string]string, len(cfgs))
+	for _, key := range keys {
+		str, err := validateValue(key, cfgs[key])
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		values[key] = str
+	}
+
+	for _, v := range checkRules(keys, values, current) {
+		errs = append(errs, v.err)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// validateValue returns the string form of the value when it is valid
+func validateValue(key string, value interface{}) (string, *errors.Error) {
+	item, ok := Instance().GetByName(key)
+	if !ok {
+		return "", violation(ErrorNotDefined, "the configure item %s is not defined", key)
+	}
+
+	str, err := StringValue(value)
+	if err != nil {
+		return "", violation(err, "invalid value of the configure item %s", key)
+	}
+
+	if err := item.Validate(str); err != nil {
+		return "", violation(err, "invalid value of the configure item %s", key)
+	}
+
+	return str, nil
+}
+
+type ruleViolation struct {
+	rule Rule
+	err  *errors.Error
+}
+
+// checkRules checks the rules depend on the keys with the valid values, the
+// current values are used for the keys of the rules which are not changed
+func checkRules(keys []string, values map[string]string, current func(key string) string) []*ruleViolation {
+	violations := make([]*ruleViolation, 0)
+	for _, rule := range Instance().GetRules(keys...) {
+		effective := make(map[string]string, len(rule.Keys))
+		for _, key := range rule.Keys {
+			if value, ok := values[key]; ok {
+				effective[key] = value
+			} else if current != nil {
+				effective[key] = current(key)
+			}
+		}
+
+		if err := rule.Check(effective); err != nil {
+			violations = append(violations, &ruleViolation{
+				rule: rule,
+				err:  violation(err, "the configure items %v violate the rule", rule.Keys),
+			})
+		}
+	}
+	return violations
+}
+
+func violation(err error, format string, args ...interface{}) *errors.Error {
+	return errors.BadRequestError(err).WithMessage(format, args...)
+}
+
+// CheckEditable returns the forbidden error when any of the configure items is
+// in the system scope or not editable
+func CheckEditable(cfgs map[string]interface{}) error {
+	keys := make([]string, 0)
+	for key := range cfgs {
+		if item, ok := Instance().GetByName(key); ok && !editable(item) {
+			keys = append(keys, key)
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil
+	}
+
+	sort.Strings(keys)
+	return errors.ForbiddenError(nil).WithMessage("the configure items are not editable: %s", strings.Join(keys, ", "))
+}
+
+func editable(item *Item) bool {
+	return item.Scope != SystemScope && item.Editable
+}
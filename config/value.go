@@ -1,6 +1,10 @@
 package config
 
 import (
+	"net"
+	"net/url"
+	"time"
+
 	"github.com/ling-server/core/errors"
 	"github.com/ling-server/core/log"
 )
@@ -32,10 +36,13 @@ func NewConfigureValue(name, value string) (*ConfigureValue, error) {
 	return result, err
 }
 
+// GetString - return the string value of current value, the secret reference
+// in the value, e.g. file:///run/secrets/db, is resolved by the secret provider
+// when the SecretRef of the item is enabled
 func (c *ConfigureValue) GetString() string {
 	// Any type has the string value
-	if _, ok := Instance().GetByName(c.Name); ok {
-		return c.Value
+	if item, ok := Instance().GetByName(c.Name); ok {
+		return dereference(item, c.Value)
 	}
 	return ""
 }
@@ -47,7 +54,7 @@ func (c *ConfigureValue) GetName() string {
 // GetInt - return the int value of current value
 func (c *ConfigureValue) GetInt() int {
 	if item, ok := Instance().GetByName(c.Name); ok {
-		val, err := item.ItemType.get(c.Value)
+		val, err := item.ItemType.Get(c.Value)
 		if err != nil {
 			log.Errorf("GetInt failed, error: %+v", err)
 			return 0
@@ -63,7 +70,7 @@ func (c *ConfigureValue) GetInt() int {
 // GetInt64 - return the int64 value of current value
 func (c *ConfigureValue) GetInt64() int64 {
 	if item, ok := Instance().GetByName(c.Name); ok {
-		val, err := item.ItemType.get(c.Value)
+		val, err := item.ItemType.Get(c.Value)
 		if err != nil {
 			log.Errorf("GetInt64 failed, error: %+v", err)
 			return 0
@@ -79,7 +86,7 @@ func (c *ConfigureValue) GetInt64() int64 {
 // GetFloat64 - return the float64 value of current value
 func (c *ConfigureValue) GetFloat64() float64 {
 	if item, ok := Instance().GetByName(c.Name); ok {
-		val, err := item.ItemType.get(c.Value)
+		val, err := item.ItemType.Get(c.Value)
 		if err != nil {
 			log.Errorf("GetFloat64 failed, error: %+v", err)
 			return 0
@@ -95,7 +102,7 @@ func (c *ConfigureValue) GetFloat64() float64 {
 // GetBool - return the bool value of current setting
 func (c *ConfigureValue) GetBool() bool {
 	if item, ok := Instance().GetByName(c.Name); ok {
-		val, err := item.ItemType.get(c.Value)
+		val, err := item.ItemType.Get(c.Value)
 		if err != nil {
 			log.Errorf("GetBool failed, error: %+v", err)
 			return false
@@ -112,7 +119,7 @@ func (c *ConfigureValue) GetBool() bool {
 func (c *ConfigureValue) GetStringToStringMap() map[string]string {
 	result := map[string]string{}
 	if item, ok := Instance().GetByName(c.Name); ok {
-		val, err := item.ItemType.get(c.Value)
+		val, err := item.ItemType.Get(c.Value)
 		if err != nil {
 			log.Errorf("The GetStringToStringMap failed, error: %+v", err)
 			return result
@@ -125,10 +132,90 @@ func (c *ConfigureValue) GetStringToStringMap() map[string]string {
 	return result
 }
 
+// GetDuration - return the duration value of current value
+func (c *ConfigureValue) GetDuration() time.Duration {
+	if item, ok := Instance().GetByName(c.Name); ok {
+		val, err := item.ItemType.Get(c.Value)
+		if err != nil {
+			log.Errorf("GetDuration failed, error: %+v", err)
+			return 0
+		}
+		if durationValue, suc := val.(time.Duration); suc {
+			return durationValue
+		}
+	}
+	log.Errorf("GetDuration failed, the current value's metadata is not defined, %+v", c)
+	return 0
+}
+
+// GetURL - return the url value of current value
+func (c *ConfigureValue) GetURL() *url.URL {
+	if item, ok := Instance().GetByName(c.Name); ok {
+		val, err := item.ItemType.Get(c.Value)
+		if err != nil {
+			log.Errorf("GetURL failed, error: %+v", err)
+			return nil
+		}
+		if urlValue, suc := val.(*url.URL); suc {
+			return urlValue
+		}
+	}
+	log.Errorf("GetURL failed, the current value's metadata is not defined, %+v", c)
+	return nil
+}
+
+// GetStringSlice - return the string slice of current value
+func (c *ConfigureValue) GetStringSlice() []string {
+	if item, ok := Instance().GetByName(c.Name); ok {
+		val, err := item.ItemType.Get(c.Value)
+		if err != nil {
+			log.Errorf("GetStringSlice failed, error: %+v", err)
+			return []string{}
+		}
+		if sliceValue, suc := val.([]string); suc {
+			return sliceValue
+		}
+	}
+	log.Errorf("GetStringSlice failed, the current value's metadata is not defined, %+v", c)
+	return []string{}
+}
+
+// GetCIDRs - return the ip networks of current value
+func (c *ConfigureValue) GetCIDRs() []*net.IPNet {
+	if item, ok := Instance().GetByName(c.Name); ok {
+		val, err := item.ItemType.Get(c.Value)
+		if err != nil {
+			log.Errorf("GetCIDRs failed, error: %+v", err)
+			return []*net.IPNet{}
+		}
+		if cidrsValue, suc := val.([]*net.IPNet); suc {
+			return cidrsValue
+		}
+	}
+	log.Errorf("GetCIDRs failed, the current value's metadata is not defined, %+v", c)
+	return []*net.IPNet{}
+}
+
+// GetByteSize - return the bytes of current value
+func (c *ConfigureValue) GetByteSize() int64 {
+	if item, ok := Instance().GetByName(c.Name); ok {
+		val, err := item.ItemType.Get(c.Value)
+		if err != nil {
+			log.Errorf("GetByteSize failed, error: %+v", err)
+			return 0
+		}
+		if int64Value, suc := val.(int64); suc {
+			return int64Value
+		}
+	}
+	log.Errorf("GetByteSize failed, the current value's metadata is not defined, %+v", c)
+	return 0
+}
+
 // GetAnyType get the interface{} of current value
 func (c *ConfigureValue) GetAnyType() (interface{}, error) {
 	if item, ok := Instance().GetByName(c.Name); ok {
-		return item.ItemType.get(c.Value)
+		return item.ItemType.Get(c.Value)
 	}
 	return nil, ErrorNotDefined
 }
@@ -136,15 +223,17 @@ func (c *ConfigureValue) GetAnyType() (interface{}, error) {
 // Validate - to validate configure items, if passed, return nil, else return error
 func (c *ConfigureValue) Validate() error {
 	if item, ok := Instance().GetByName(c.Name); ok {
-		return item.ItemType.validate(c.Value)
+		return item.Validate(c.Value)
 	}
 	return ErrorNotDefined
 }
 
-// GetPassword ...
+// GetPassword - return the password of current value, the secret reference in
+// the value, e.g. env://DB_PASS, is resolved by the secret provider when the
+// SecretRef of the item is enabled
 func (c *ConfigureValue) GetPassword() string {
-	if _, ok := Instance().GetByName(c.Name); ok {
-		return c.Value
+	if item, ok := Instance().GetByName(c.Name); ok {
+		return dereference(item, c.Value)
 	}
 	log.Errorf("GetPassword failed, metadata not defined: %v", c.Name)
 	return ""
@@ -153,7 +242,7 @@ func (c *ConfigureValue) GetPassword() string {
 // Set - set this configure item to configure store
 func (c *ConfigureValue) Set(name, value string) error {
 	if item, ok := Instance().GetByName(name); ok {
-		err := item.ItemType.validate(value)
+		err := item.Validate(value)
 		if err == nil {
 			c.Name = name
 			c.Value = value
@@ -0,0 +1,249 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ling-server/core/log"
+)
+
+// the buffer size of the event channel for each subscriber
+const watchBufferSize = 64
+
+// Event is the change event of the configure item, the values of the password
+// items are redacted
+type Event struct {
+	Key      string    `json:"key"`
+	OldValue string    `json:"old_value"`
+	NewValue string    `json:"new_value"`
+	Time     time.Time `json:"time"`
+	// Tenant the tenant whose override is changed, empty for the global value
+	Tenant string `json:"tenant,omitempty"`
+	// Origin the id of the instance where the change happened
+	Origin string `json:"origin,omitempty"`
+	// Unchanged is true when the value is written without change, the event
+	// only invalidates the cached values of the replicas
+	Unchanged bool `json:"unchanged,omitempty"`
+}
+
+// NewEvent returns the change event of the configure item
+func NewEvent(key, oldValue, newValue, origin string) *Event {
+	return &Event{
+		Key:      key,
+		OldValue: Redact(key, oldValue),
+		NewValue: Redact(key, newValue),
+		Time:     time.Now(),
+		Origin:   origin,
+	}
+}
+
+// Watcher delivers the change events of the configure items to the subscribers
+type Watcher interface {
+	// Watch returns the channel of the change events of the keys, all the keys
+	// are watched when no key specified, the channel is closed when ctx is done
+	Watch(ctx context.Context, keys ...string) (<-chan *Event, error)
+}
+
+// Notifier is notified when the configure values are changed
+type Notifier interface {
+	Notify(ctx context.Context, events ...*Event) error
+}
+
+// Watch watches the change events of the keys from the config manager of the ctx
+func Watch(ctx context.Context, keys ...string) (<-chan *Event, error) {
+	w, ok := GetConfigManager(ctx).(Watcher)
+	if !ok {
+		return nil, errors.New("the config manager does not support watch")
+	}
+	return w.Watch(ctx, keys...)
+}
+
+// Diff returns the change events between the old and new values sorted by key
+func Diff(oldValues, newValues map[string]string, origin string) []*Event {
+	keys := make([]string, 0, len(newValues))
+	for key := range oldValues {
+		if _, ok := newValues[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	for key := range newValues {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	events := make([]*Event, 0)
+	for _, key := range keys {
+		oldValue, newValue := oldValues[key], newValues[key]
+		if oldValue != newValue {
+			events = append(events, NewEvent(key, oldValue, newValue, origin))
+		}
+	}
+	return events
+}
+
+var _ Watcher = (*Broadcaster)(nil)
+var _ Notifier = (*Broadcaster)(nil)
+
+type subscriber struct {
+	keys map[string]struct{}
+	ch   chan *Event
+}
+
+func (s *subscriber) match(key string) bool {
+	if len(s.keys) == 0 {
+		return true
+	}
+	_, ok := s.keys[key]
+	return ok
+}
+
+// Broadcaster fans out the events it is notified to the subscribers
+type Broadcaster struct {
+	mu          sync.RWMutex
+	subscribers map[*subscriber]struct{}
+}
+
+// NewBroadcaster returns an instance of the Broadcaster
+func NewBroadcaster() *Broadcaster {
+	return &Broadcaster{subscribers: map[*subscriber]struct{}{}}
+}
+
+// Watch subscribes the events of the keys
+func (b *Broadcaster) Watch(ctx context.Context, keys ...string) (<-chan *Event, error) {
+	sub := &subscriber{
+		keys: make(map[string]struct{}, len(keys)),
+		ch:   make(chan *Event, watchBufferSize),
+	}
+	for _, key := range keys {
+		sub.keys[key] = struct{}{}
+	}
+
+	b.mu.Lock()
+	b.subscribers[sub] = struct{}{}
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		delete(b.subscribers, sub)
+		close(sub.ch)
+	}()
+
+	return sub.ch, nil
+}
+
+// Notify delivers the events to the subscribers, the event is dropped for the
+// subscriber whose channel is full so that the slow subscriber will not block
+// the changes of configure values
+func (b *Broadcaster) Notify(ctx context.Context, events ...*Event) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for sub := range b.subscribers {
+		for _, e := range events {
+			if !sub.match(e.Key) {
+				continue
+			}
+
+			select {
+			case sub.ch <- e:
+			default:
+				log.Warningf("the subscriber of the config changes is slow, drop the event of %s", e.Key)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Persistent is the config manager which exposes the values persisted in its
+// store as the source, e.g. the db manager
+type Persistent interface {
+	Source() Source
+}
+
+// DefaultPollingInterval is the interval of the PollingWatcher when the given one is not positive
+const DefaultPollingInterval = time.Minute
+
+// PollingWatcher detects the changes by polling the configure values from the
+// config manager periodically, the values persisted in the store are polled
+// when the manager is Persistent so the values set but not saved are ignored
+type PollingWatcher struct {
+	*Broadcaster
+	mgr      Manager
+	interval time.Duration
+	snapshot map[string]string
+}
+
+// NewPollingWatcher returns a watcher which polls the manager in the interval,
+// DefaultPollingInterval is used when the interval is not positive
+func NewPollingWatcher(mgr Manager, interval time.Duration) *PollingWatcher {
+	if interval <= 0 {
+		interval = DefaultPollingInterval
+	}
+	return &PollingWatcher{
+		Broadcaster: NewBroadcaster(),
+		mgr:         mgr,
+		interval:    interval,
+	}
+}
+
+// Start polls the configure values until the ctx is done, the snapshot of the
+// values is taken at the beginning so the changes after Start are delivered
+func (w *PollingWatcher) Start(ctx context.Context) {
+	w.poll(ctx)
+
+	interval := w.interval
+	if interval <= 0 {
+		interval = DefaultPollingInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.poll(ctx)
+		}
+	}
+}
+
+func (w *PollingWatcher) poll(ctx context.Context) {
+	values, err := w.load(ctx)
+	if err != nil {
+		log.Errorf("failed to load the configure values when polling, error: %v", err)
+		return
+	}
+
+	if w.snapshot != nil {
+		if err := w.Notify(ctx, Diff(w.snapshot, values, "")...); err != nil {
+			log.Errorf("failed to notify the config changes, error: %v", err)
+		}
+	}
+	w.snapshot = values
+}
+
+func (w *PollingWatcher) load(ctx context.Context) (map[string]string, error) {
+	if p, ok := w.mgr.(Persistent); ok {
+		return p.Source().Load(ctx)
+	}
+
+	if err := w.mgr.Load(ctx); err != nil {
+		return nil, err
+	}
+	values := map[string]string{}
+	for _, item := range Instance().GetAll() {
+		if cv := w.mgr.Get(ctx, item.Name); cv != nil {
+			values[item.Name] = cv.Value
+		}
+	}
+	return values, nil
+}
@@ -191,6 +191,11 @@ func ErrorCode(err error) string {
 		return ""
 	}
 
+	// the code of the first error is used for the errors
+	if errs, ok := err.(Errors); ok && len(errs) > 0 {
+		return ErrorCode(errs[0])
+	}
+
 	var e *Error
 	if ok := As(err, &e); ok && e.Code != "" {
 		return e.Code
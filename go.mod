@@ -1,9 +1,10 @@
 module github.com/ling-server/core
 
-go 1.18
+go 1.21
 
 require (
 	github.com/beego/beego v1.12.11
+	github.com/go-logr/logr v1.2.3
 	github.com/go-openapi/errors v0.20.3
 	github.com/google/uuid v1.1.2
 	github.com/jackc/pgconn v1.13.0
@@ -15,6 +16,7 @@ require (
 	go.opentelemetry.io/otel/sdk v1.11.0
 	go.opentelemetry.io/otel/trace v1.11.0
 	golang.org/x/text v0.3.7
+	gopkg.in/yaml.v3 v3.0.1
 	gorm.io/gorm v1.24.0
 )
 
@@ -23,7 +25,6 @@ require (
 	github.com/cespare/xxhash/v2 v2.1.2 // indirect
 	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
 	github.com/felixge/httpsnoop v1.0.3 // indirect
-	github.com/go-logr/logr v1.2.3 // indirect
 	github.com/go-logr/stdr v1.2.2 // indirect
 	github.com/golang/protobuf v1.5.2 // indirect
 	github.com/grpc-ecosystem/grpc-gateway/v2 v2.7.0 // indirect
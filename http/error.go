@@ -98,5 +98,9 @@ func apiError(err error) (statusCode int, errPayload, stackTrace string) {
 	if _, ok := err.(*errors.Error); ok {
 		fullStack = err.(*errors.Error).StackTrace()
 	}
+	// the errors are enveloped already
+	if errs, ok := err.(errors.Errors); ok {
+		return code, errs.Error(), fullStack
+	}
 	return code, errors.NewErrs(err).Error(), fullStack
 }
@@ -0,0 +1,195 @@
+package log
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// OverflowPolicy is the policy of the asynchronous logger when the buffer is full
+type OverflowPolicy int
+
+const (
+	// BlockOnFull blocks the caller until there is room in the buffer
+	BlockOnFull OverflowPolicy = iota
+	// DropOnFull drops the new log
+	DropOnFull
+	// DropLowestOnFull drops the oldest log of the lowest level in the buffer,
+	// or the new log when its level is not higher than the ones in the buffer
+	DropLowestOnFull
+)
+
+type asyncEntry struct {
+	l      *Logger
+	record *Record
+}
+
+// asyncQueue is the bounded ring buffer of the logs, the logs are formatted
+// and written by the loggers which produce them in one goroutine
+type asyncQueue struct {
+	policy  OverflowPolicy
+	dropped uint64
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	buf     []asyncEntry
+	head    int
+	count   int
+	busy    bool
+	closed  bool
+	stopped chan struct{}
+}
+
+func newAsyncQueue(size int, policy OverflowPolicy) *asyncQueue {
+	if size <= 0 {
+		size = 1024
+	}
+	q := &asyncQueue{
+		policy:  policy,
+		buf:     make([]asyncEntry, size),
+		stopped: make(chan struct{}),
+	}
+	q.cond = sync.NewCond(&q.mu)
+	go q.loop()
+	return q
+}
+
+// push adds the record to the queue, false is returned when the queue is
+// closed and the record should be written synchronously
+func (q *asyncQueue) push(l *Logger, record *Record) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for !q.closed && q.count == len(q.buf) {
+		switch q.policy {
+		case DropOnFull:
+			atomic.AddUint64(&q.dropped, 1)
+			return true
+		case DropLowestOnFull:
+			if !q.dropLowest(record.Level) {
+				atomic.AddUint64(&q.dropped, 1)
+				return true
+			}
+			atomic.AddUint64(&q.dropped, 1)
+		default:
+			q.cond.Wait()
+		}
+	}
+	if q.closed {
+		return false
+	}
+
+	q.buf[(q.head+q.count)%len(q.buf)] = asyncEntry{l: l, record: record}
+	q.count++
+	q.cond.Broadcast()
+	return true
+}
+
+// dropLowest removes the oldest entry of the lowest level which is lower than
+// the level, false is returned when there is no such entry
+func (q *asyncQueue) dropLowest(lvl Level) bool {
+	index := -1
+	for i := 0; i < q.count; i++ {
+		e := q.buf[(q.head+i)%len(q.buf)]
+		if e.record.Level < lvl && (index < 0 || e.record.Level < q.buf[(q.head+index)%len(q.buf)].record.Level) {
+			index = i
+		}
+	}
+	if index < 0 {
+		return false
+	}
+
+	for i := index; i < q.count-1; i++ {
+		q.buf[(q.head+i)%len(q.buf)] = q.buf[(q.head+i+1)%len(q.buf)]
+	}
+	q.count--
+	q.buf[(q.head+q.count)%len(q.buf)] = asyncEntry{}
+	return true
+}
+
+func (q *asyncQueue) loop() {
+	defer close(q.stopped)
+
+	for {
+		q.mu.Lock()
+		for q.count == 0 && !q.closed {
+			q.cond.Wait()
+		}
+		if q.count == 0 && q.closed {
+			q.mu.Unlock()
+			return
+		}
+		e := q.buf[q.head]
+		q.buf[q.head] = asyncEntry{}
+		q.head = (q.head + 1) % len(q.buf)
+		q.count--
+		q.busy = true
+		q.cond.Broadcast()
+		q.mu.Unlock()
+
+		_ = e.l.write(e.record)
+
+		q.mu.Lock()
+		q.busy = false
+		q.cond.Broadcast()
+		q.mu.Unlock()
+	}
+}
+
+// flush waits until the logs in the queue are written
+func (q *asyncQueue) flush() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for q.count > 0 || q.busy {
+		q.cond.Wait()
+	}
+}
+
+// close writes the logs in the queue and stops the queue
+func (q *asyncQueue) close() {
+	q.mu.Lock()
+	q.closed = true
+	q.cond.Broadcast()
+	q.mu.Unlock()
+
+	<-q.stopped
+}
+
+// SetAsync makes Logger l and the loggers cloned from it afterwards format and
+// write the logs asynchronously, the logs are buffered in the ring buffer of
+// the size and the policy applies when the buffer is full
+func (l *Logger) SetAsync(size int, policy OverflowPolicy) {
+	if old := l.async.Swap(newAsyncQueue(size, policy)); old != nil {
+		old.close()
+	}
+}
+
+// Flush waits until the buffered logs of Logger l are written, it returns
+// immediately when l is not asynchronous
+func (l *Logger) Flush() {
+	if q := l.async.Load(); q != nil {
+		q.flush()
+	}
+}
+
+// Close writes the buffered logs and makes Logger l synchronous, the loggers
+// cloned from l write the logs synchronously too
+func (l *Logger) Close() {
+	if q := l.async.Swap(nil); q != nil {
+		q.close()
+	}
+}
+
+// Dropped returns the count of the logs dropped because the buffer is full
+func (l *Logger) Dropped() uint64 {
+	q := l.async.Load()
+	if q == nil {
+		return 0
+	}
+	return atomic.LoadUint64(&q.dropped)
+}
+
+// Flush waits until the buffered logs of default logger are written
+func Flush() {
+	logger.Flush()
+}
@@ -0,0 +1,236 @@
+package log
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// messageFormatter formats the record as its message only
+type messageFormatter struct{}
+
+func (messageFormatter) Format(r *Record) ([]byte, error) {
+	return []byte(r.Message), nil
+}
+
+// gateWriter records the written logs, every write waits for the gate to be
+// released or opened
+type gateWriter struct {
+	started chan struct{}
+	gate    chan struct{}
+	once    sync.Once
+
+	mu    sync.Mutex
+	lines []string
+}
+
+func newGateWriter() *gateWriter {
+	return &gateWriter{
+		started: make(chan struct{}, 64),
+		gate:    make(chan struct{}),
+	}
+}
+
+func (w *gateWriter) Write(p []byte) (int, error) {
+	w.started <- struct{}{}
+	<-w.gate
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.lines = append(w.lines, string(p))
+	return len(p), nil
+}
+
+// wait waits until n writes are started
+func (w *gateWriter) wait(t *testing.T, n int) {
+	t.Helper()
+
+	for i := 0; i < n; i++ {
+		select {
+		case <-w.started:
+		case <-time.After(time.Second):
+			t.Fatal("the log is not written asynchronously")
+		}
+	}
+}
+
+// release lets n writes complete
+func (w *gateWriter) release(n int) {
+	for i := 0; i < n; i++ {
+		w.gate <- struct{}{}
+	}
+}
+
+// open lets all the writes complete
+func (w *gateWriter) open() {
+	w.once.Do(func() {
+		close(w.gate)
+	})
+}
+
+func (w *gateWriter) written() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return append([]string(nil), w.lines...)
+}
+
+// newBlockedLogger returns the asynchronous logger whose writing goroutine is
+// blocked in writing the log "blocked", so the queue is empty and the logs are
+// buffered until the gate of the writer is opened
+func newBlockedLogger(t *testing.T, size int, policy OverflowPolicy) (*Logger, *gateWriter) {
+	w := newGateWriter()
+	l := New(w, messageFormatter{}, DebugLevel)
+	l.SetAsync(size, policy)
+	t.Cleanup(func() {
+		w.open()
+		l.Close()
+	})
+
+	l.Info("blocked")
+	w.wait(t, 1)
+	return l, w
+}
+
+func assertWritten(t *testing.T, w *gateWriter, expected ...string) {
+	t.Helper()
+
+	if lines := w.written(); !reflect.DeepEqual(lines, expected) {
+		t.Errorf("expected the logs %q, but got %q", expected, lines)
+	}
+}
+
+func TestAsyncBlockOnFull(t *testing.T) {
+	l, w := newBlockedLogger(t, 1, BlockOnFull)
+	l.Info("a")
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		l.Info("b")
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("the log is not blocked when the buffer is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	w.open()
+	<-done
+	l.Flush()
+
+	assertWritten(t, w, "blocked", "a", "b")
+	if dropped := l.Dropped(); dropped != 0 {
+		t.Errorf("expected no dropped log, but got %d", dropped)
+	}
+}
+
+func TestAsyncDropOnFull(t *testing.T) {
+	l, w := newBlockedLogger(t, 2, DropOnFull)
+	l.Info("a")
+	l.Info("b")
+	l.Error("c")
+	l.Info("d")
+
+	w.open()
+	l.Flush()
+
+	assertWritten(t, w, "blocked", "a", "b")
+	if dropped := l.Dropped(); dropped != 2 {
+		t.Errorf("expected 2 dropped logs, but got %d", dropped)
+	}
+}
+
+func TestAsyncDropLowestOnFull(t *testing.T) {
+	l, w := newBlockedLogger(t, 3, DropLowestOnFull)
+	l.Debug("debug1")
+	l.Info("info")
+	l.Debug("debug2")
+
+	// the oldest log of the lowest level is dropped
+	l.Warning("warning")
+	// the logs after the dropped one are shifted in order
+	l.Error("error")
+	// the new log is dropped when its level is not higher than the buffered ones
+	l.Debug("debug3")
+	l.Info("info2")
+
+	w.open()
+	l.Flush()
+
+	assertWritten(t, w, "blocked", "info", "warning", "error")
+	if dropped := l.Dropped(); dropped != 4 {
+		t.Errorf("expected 4 dropped logs, but got %d", dropped)
+	}
+}
+
+func TestAsyncDropLowestOnFullWrapped(t *testing.T) {
+	l, w := newBlockedLogger(t, 3, DropLowestOnFull)
+	l.Info("a")
+	l.Info("b")
+	l.Info("c")
+
+	// write "blocked" and "a", then block in writing "b", so the head of the
+	// ring buffer is moved to the last slot
+	w.release(2)
+	w.wait(t, 2)
+
+	l.Debug("d")
+	l.Info("e")
+	// "d" is dropped and "e" is shifted across the end of the ring buffer
+	l.Error("f")
+
+	w.open()
+	l.Flush()
+
+	assertWritten(t, w, "blocked", "a", "b", "c", "e", "f")
+	if dropped := l.Dropped(); dropped != 1 {
+		t.Errorf("expected 1 dropped log, but got %d", dropped)
+	}
+}
+
+func TestAsyncFlushAfterClose(t *testing.T) {
+	l, w := newBlockedLogger(t, 4, BlockOnFull)
+	clone := l.WithField("k", "v")
+	l.Info("a")
+	clone.Info("b")
+
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		l.Close()
+	}()
+	w.open()
+
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("the logger is not closed")
+	}
+	// the buffered logs are written when closing
+	assertWritten(t, w, "blocked", "a", "b")
+
+	flushed := make(chan struct{})
+	go func() {
+		defer close(flushed)
+		l.Flush()
+		clone.Flush()
+	}()
+	select {
+	case <-flushed:
+	case <-time.After(time.Second):
+		t.Fatal("the flush after closing is blocked")
+	}
+
+	// the logs are written synchronously after closing, by the clone too
+	l.Info("c")
+	clone.Info("d")
+	assertWritten(t, w, "blocked", "a", "b", "c", "d")
+
+	if dropped := l.Dropped(); dropped != 0 {
+		t.Errorf("expected no dropped log after closing, but got %d", dropped)
+	}
+}
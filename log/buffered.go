@@ -0,0 +1,213 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// ErrBufferFull is returned when the buffer of the writer is full
+var ErrBufferFull = errors.New("the buffer of the log writer is full")
+
+const (
+	defaultBatchSize     = 100
+	defaultFlushInterval = time.Second
+	defaultBufferSize    = 1024
+	defaultBlockTimeout  = 100 * time.Millisecond
+)
+
+type bufferOptions struct {
+	batchSize     int
+	flushInterval time.Duration
+	bufferSize    int
+	blockTimeout  time.Duration
+}
+
+// complete sets the defaults of the options which are not positive
+func (o *bufferOptions) complete() {
+	if o.batchSize <= 0 {
+		o.batchSize = defaultBatchSize
+	}
+	if o.flushInterval <= 0 {
+		o.flushInterval = defaultFlushInterval
+	}
+	if o.bufferSize <= 0 {
+		o.bufferSize = defaultBufferSize
+	}
+	if o.blockTimeout <= 0 {
+		o.blockTimeout = defaultBlockTimeout
+	}
+}
+
+type bufferedEntry struct {
+	record *Record
+	data   []byte
+}
+
+// bufferedWriter buffers the logs in the bounded queue and sends them in
+// batches from the background goroutine, so the loggers never wait for the
+// network. The send returns the count of the entries sent, the rest of the
+// batch are written to the fallback logger
+type bufferedWriter struct {
+	target string
+	opts   bufferOptions
+	send   func(batch []*bufferedEntry) (int, error)
+
+	entries  chan *bufferedEntry
+	flush    chan chan struct{}
+	done     chan struct{}
+	closed   chan struct{}
+	once     sync.Once
+	mu       sync.RWMutex
+	fallback *Logger
+}
+
+func newBufferedWriter(target string, opts bufferOptions, send func([]*bufferedEntry) (int, error)) *bufferedWriter {
+	opts.complete()
+	w := &bufferedWriter{
+		target:  target,
+		opts:    opts,
+		send:    send,
+		entries: make(chan *bufferedEntry, opts.bufferSize),
+		flush:   make(chan chan struct{}),
+		done:    make(chan struct{}),
+		closed:  make(chan struct{}),
+	}
+	go w.loop()
+	return w
+}
+
+// SetFallback sets the logger the logs are written to when they fail to be sent
+func (w *bufferedWriter) SetFallback(l *Logger) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.fallback = l
+}
+
+// Write buffers the log
+func (w *bufferedWriter) Write(p []byte) (int, error) {
+	return w.enqueue(&bufferedEntry{data: append([]byte(nil), p...)})
+}
+
+// WriteRecord buffers the log, the record is written to the fallback logger
+// when the log fails to be sent
+func (w *bufferedWriter) WriteRecord(r *Record, p []byte) (int, error) {
+	return w.enqueue(&bufferedEntry{record: r, data: append([]byte(nil), p...)})
+}
+
+// Flush sends the buffered logs and waits until they are sent
+func (w *bufferedWriter) Flush() {
+	ch := make(chan struct{})
+	select {
+	case w.flush <- ch:
+		<-ch
+	case <-w.closed:
+	}
+}
+
+// Close sends the buffered logs and stops the writer, the writes after closing fail
+func (w *bufferedWriter) Close() error {
+	w.once.Do(func() {
+		close(w.done)
+	})
+	<-w.closed
+	return nil
+}
+
+func (w *bufferedWriter) enqueue(e *bufferedEntry) (int, error) {
+	select {
+	case <-w.done:
+		return 0, io.ErrClosedPipe
+	default:
+	}
+
+	select {
+	case w.entries <- e:
+		return len(e.data), nil
+	default:
+	}
+
+	// backpressure, block the writer until there is room in the buffer or timeout
+	timer := time.NewTimer(w.opts.blockTimeout)
+	defer timer.Stop()
+	select {
+	case w.entries <- e:
+		return len(e.data), nil
+	case <-timer.C:
+		return 0, ErrBufferFull
+	case <-w.done:
+		return 0, io.ErrClosedPipe
+	}
+}
+
+func (w *bufferedWriter) loop() {
+	defer close(w.closed)
+
+	ticker := time.NewTicker(w.opts.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*bufferedEntry, 0, w.opts.batchSize)
+	send := func() {
+		if len(batch) > 0 {
+			w.deliver(batch)
+			batch = make([]*bufferedEntry, 0, w.opts.batchSize)
+		}
+	}
+	drain := func() {
+		for {
+			select {
+			case e := <-w.entries:
+				if batch = append(batch, e); len(batch) >= w.opts.batchSize {
+					send()
+				}
+			default:
+				send()
+				return
+			}
+		}
+	}
+
+	for {
+		select {
+		case e := <-w.entries:
+			if batch = append(batch, e); len(batch) >= w.opts.batchSize {
+				send()
+			}
+		case <-ticker.C:
+			send()
+		case ch := <-w.flush:
+			drain()
+			close(ch)
+		case <-w.done:
+			drain()
+			return
+		}
+	}
+}
+
+func (w *bufferedWriter) deliver(batch []*bufferedEntry) {
+	n, err := w.send(batch)
+	if err == nil {
+		return
+	}
+
+	w.mu.RLock()
+	fallback := w.fallback
+	w.mu.RUnlock()
+	if fallback == nil {
+		return
+	}
+
+	failed := batch[n:]
+	fallback.Errorf("failed to send %d logs to %s, error: %v", len(failed), w.target, err)
+	for _, e := range failed {
+		r := e.record
+		if r == nil {
+			r = NewRecord(time.Now(), string(bytes.TrimRight(e.data, "\n")), "", InfoLevel)
+		}
+		_ = fallback.output(r)
+	}
+}
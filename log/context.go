@@ -1,6 +1,10 @@
 package log
 
-import "context"
+import (
+	"context"
+
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
 
 var (
 	// G - shortcut to get logger from the context
@@ -18,15 +22,16 @@ func WithLogger(ctx context.Context, logger *Logger) context.Context {
 
 // GetLogger retrieves the current logger from the context.
 // If no logger is available, the default logger is returned.
+// The trace_id and span_id fields are added when there is span in the context.
 func GetLogger(ctx context.Context) *Logger {
 	if ctx == nil {
 		return L
 	}
 
-	logger := ctx.Value(loggerKey{})
-	if logger == nil {
-		return L
+	l := L
+	if logger := ctx.Value(loggerKey{}); logger != nil {
+		l = logger.(*Logger)
 	}
 
-	return logger.(*Logger)
+	return l.WithSpan(oteltrace.SpanFromContext(ctx))
 }
@@ -0,0 +1,319 @@
+package log
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const backupTimeFormat = "20060102T150405.000"
+
+type fileOptions struct {
+	maxSize    int64
+	interval   time.Duration
+	maxBackups int
+	maxAge     time.Duration
+	compress   bool
+}
+
+// FileOption the option of the FileWriter
+type FileOption func(*fileOptions)
+
+// MaxSize rotates the file when its size exceeds the bytes
+func MaxSize(bytes int64) FileOption {
+	return func(o *fileOptions) {
+		o.maxSize = bytes
+	}
+}
+
+// RotateInterval rotates the file every interval, e.g. 24 * time.Hour rotates at midnight in UTC
+func RotateInterval(interval time.Duration) FileOption {
+	return func(o *fileOptions) {
+		o.interval = interval
+	}
+}
+
+// MaxBackups keeps at most the count of the rotated files
+func MaxBackups(count int) FileOption {
+	return func(o *fileOptions) {
+		o.maxBackups = count
+	}
+}
+
+// MaxAge removes the rotated files older than the age
+func MaxAge(age time.Duration) FileOption {
+	return func(o *fileOptions) {
+		o.maxAge = age
+	}
+}
+
+// Compress gzips the rotated files
+func Compress(compress bool) FileOption {
+	return func(o *fileOptions) {
+		o.compress = compress
+	}
+}
+
+// FileWriter is the io.Writer writes the logs to the file with rotation, the
+// rotated files are named as <path>.<time>, e.g. core.log.20060102T150405.000,
+// and <path>.<time>.gz when they are compressed
+type FileWriter struct {
+	path string
+	opts *fileOptions
+
+	mu         sync.Mutex
+	file       *os.File
+	size       int64
+	nextRotate time.Time
+	// cleanMU serializes the compression and removal of the rotated files
+	cleanMU sync.Mutex
+}
+
+// NewFileWriter returns the FileWriter writes to the file of the path, the
+// directory of the path is created if it does not exist
+func NewFileWriter(path string, opts ...FileOption) (*FileWriter, error) {
+	o := &fileOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	w := &FileWriter{path: path, opts: o}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Write writes the log to the file, the file is rotated before writing when
+// the size or the interval is exceeded
+func (w *FileWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	if w.shouldRotate(int64(len(p))) {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Rotate rotates the file
+func (w *FileWriter) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.rotate()
+}
+
+// Reopen closes and reopens the file of the path, it is used after the file is
+// moved by the external tools, e.g. logrotate
+func (w *FileWriter) Reopen() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file != nil {
+		if err := w.file.Close(); err != nil {
+			return err
+		}
+	}
+	return w.open()
+}
+
+// ReopenOnSignal reopens the file when the SIGHUP is received, call the
+// returned function to stop it
+func (w *FileWriter) ReopenOnSignal() (stop func()) {
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(ch, syscall.SIGHUP)
+
+	go func() {
+		for {
+			select {
+			case <-ch:
+				if err := w.Reopen(); err != nil {
+					fmt.Fprintf(os.Stderr, "failed to reopen the log file %s, error: %v\n", w.path, err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+		})
+	}
+}
+
+// Close closes the file, the writes after closing fail
+func (w *FileWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+func (w *FileWriter) open() error {
+	f, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	w.file, w.size = f, info.Size()
+	if w.opts.interval > 0 {
+		w.nextRotate = time.Now().Truncate(w.opts.interval).Add(w.opts.interval)
+	}
+	return nil
+}
+
+func (w *FileWriter) shouldRotate(n int64) bool {
+	if w.opts.maxSize > 0 && w.size > 0 && w.size+n > w.opts.maxSize {
+		return true
+	}
+	return w.opts.interval > 0 && !time.Now().Before(w.nextRotate)
+}
+
+func (w *FileWriter) rotate() error {
+	if w.file != nil {
+		if err := w.file.Close(); err != nil {
+			return err
+		}
+		w.file = nil
+	}
+
+	backup := w.path + "." + time.Now().Format(backupTimeFormat)
+	if err := os.Rename(w.path, backup); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := w.open(); err != nil {
+		return err
+	}
+
+	go w.clean(backup)
+	return nil
+}
+
+// clean compresses the rotated file and removes the ones exceed the retention
+func (w *FileWriter) clean(backup string) {
+	w.cleanMU.Lock()
+	defer w.cleanMU.Unlock()
+
+	if w.opts.compress {
+		if err := compressFile(backup); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to compress the log file %s, error: %v\n", backup, err)
+		}
+	}
+
+	if w.opts.maxBackups <= 0 && w.opts.maxAge <= 0 {
+		return
+	}
+
+	backups, err := w.backups()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to list the rotated log files of %s, error: %v\n", w.path, err)
+		return
+	}
+	for i, b := range backups {
+		expired := w.opts.maxAge > 0 && time.Since(b.time) > w.opts.maxAge
+		if (w.opts.maxBackups > 0 && i >= w.opts.maxBackups) || expired {
+			if err := os.Remove(b.path); err != nil && !os.IsNotExist(err) {
+				fmt.Fprintf(os.Stderr, "failed to remove the log file %s, error: %v\n", b.path, err)
+			}
+		}
+	}
+}
+
+type backupFile struct {
+	path string
+	time time.Time
+}
+
+// backups returns the rotated files, the newest first
+func (w *FileWriter) backups() ([]*backupFile, error) {
+	dir, prefix := filepath.Dir(w.path), filepath.Base(w.path)+"."
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	backups := make([]*backupFile, 0)
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		t, err := time.ParseInLocation(backupTimeFormat, strings.TrimSuffix(strings.TrimPrefix(name, prefix), ".gz"), time.Local)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, &backupFile{path: filepath.Join(dir, name), time: t})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].time.After(backups[j].time)
+	})
+	return backups, nil
+}
+
+func compressFile(path string) (err error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = dst.Close()
+			_ = os.Remove(path + ".gz")
+		}
+	}()
+
+	gz := gzip.NewWriter(dst)
+	if _, err = io.Copy(gz, src); err != nil {
+		return err
+	}
+	if err = gz.Close(); err != nil {
+		return err
+	}
+	if err = dst.Close(); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,61 @@
+package log
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// LevelState is the state of the levels returned by the LevelHandler
+type LevelState struct {
+	Level Level            `json:"level"`
+	Named map[string]Level `json:"named"`
+}
+
+// levelRequest is the request to change the level, the level of the logger is
+// changed when the name is empty, otherwise the override of the name is set,
+// or removed when the level is empty
+type levelRequest struct {
+	Name  string `json:"name,omitempty"`
+	Level string `json:"level"`
+}
+
+// LevelHandler returns the handler which reads the levels of Logger l and the
+// named loggers by GET and changes them by PUT, e.g. {"level": "debug"} or
+// {"name": "config", "level": "debug"}
+func LevelHandler(l *Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut, http.MethodPost:
+			req := &levelRequest{}
+			if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+				http.Error(w, "invalid request: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			if len(req.Name) > 0 && len(req.Level) == 0 {
+				UnsetNamedLevel(req.Name)
+				break
+			}
+
+			lvl, err := ParseLevel(req.Level)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if len(req.Name) > 0 {
+				SetNamedLevel(req.Name, lvl)
+			} else {
+				l.SetLevel(lvl)
+			}
+			l.Infof("the level of the logger %q is changed to %s", req.Name, lvl)
+		default:
+			w.Header().Set("Allow", "GET, PUT, POST")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(&LevelState{Level: l.GetLevel(), Named: NamedLevels()})
+	})
+}
@@ -0,0 +1,157 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+type httpOptions struct {
+	bufferOptions
+	client     *http.Client
+	header     http.Header
+	maxRetries int
+}
+
+// HTTPOption the option of the HTTPWriter
+type HTTPOption func(*httpOptions)
+
+// HTTPClient sets the client sends the batches, the client with 10s timeout by default
+func HTTPClient(client *http.Client) HTTPOption {
+	return func(o *httpOptions) {
+		o.client = client
+	}
+}
+
+// HTTPHeader adds the header to the requests, e.g. Authorization
+func HTTPHeader(key, value string) HTTPOption {
+	return func(o *httpOptions) {
+		o.header.Add(key, value)
+	}
+}
+
+// BatchSize sets the max count of the logs in one request, 100 by default
+func BatchSize(size int) HTTPOption {
+	return func(o *httpOptions) {
+		o.batchSize = size
+	}
+}
+
+// FlushInterval sets the interval to send the batch which is not full, 1s by default
+func FlushInterval(interval time.Duration) HTTPOption {
+	return func(o *httpOptions) {
+		o.flushInterval = interval
+	}
+}
+
+// BufferSize sets the count of the logs buffered before sending, 1024 by default
+func BufferSize(size int) HTTPOption {
+	return func(o *httpOptions) {
+		o.bufferSize = size
+	}
+}
+
+// BlockTimeout sets how long the write blocks when the buffer is full, then
+// ErrBufferFull is returned and the log is written to the fallback logger, 100ms by default
+func BlockTimeout(timeout time.Duration) HTTPOption {
+	return func(o *httpOptions) {
+		o.blockTimeout = timeout
+	}
+}
+
+// MaxRetries sets the max retries of the failed request, 2 by default
+func MaxRetries(retries int) HTTPOption {
+	return func(o *httpOptions) {
+		o.maxRetries = retries
+	}
+}
+
+// HTTPWriter sends the logs to the http endpoint by POST in batches, the body
+// is the formatted logs one per line, e.g. NDJSON with the JSONFormatter. The
+// logs of the batch which fails to be sent are written to the fallback logger
+type HTTPWriter struct {
+	*bufferedWriter
+
+	url  string
+	opts *httpOptions
+}
+
+// NewHTTPWriter returns the HTTPWriter sends the logs to the url, the options
+// which are not positive are set to the defaults
+func NewHTTPWriter(url string, opts ...HTTPOption) *HTTPWriter {
+	o := &httpOptions{
+		bufferOptions: bufferOptions{
+			batchSize:     defaultBatchSize,
+			flushInterval: defaultFlushInterval,
+			bufferSize:    defaultBufferSize,
+			blockTimeout:  defaultBlockTimeout,
+		},
+		client:     &http.Client{Timeout: 10 * time.Second},
+		header:     http.Header{},
+		maxRetries: 2,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.client == nil {
+		o.client = &http.Client{Timeout: 10 * time.Second}
+	}
+	if o.maxRetries < 0 {
+		o.maxRetries = 0
+	}
+	if len(o.header.Get("Content-Type")) == 0 {
+		o.header.Set("Content-Type", "application/x-ndjson")
+	}
+
+	w := &HTTPWriter{
+		url:  url,
+		opts: o,
+	}
+	w.bufferedWriter = newBufferedWriter(url, o.bufferOptions, w.send)
+	return w
+}
+
+func (w *HTTPWriter) send(batch []*bufferedEntry) (int, error) {
+	body := &bytes.Buffer{}
+	for _, e := range batch {
+		body.Write(e.data)
+		if len(e.data) > 0 && e.data[len(e.data)-1] != '\n' {
+			body.WriteByte('\n')
+		}
+	}
+
+	var err error
+	for i := 0; i <= w.opts.maxRetries; i++ {
+		if i > 0 {
+			time.Sleep(time.Duration(i) * 100 * time.Millisecond)
+		}
+		if err = w.post(body.Bytes()); err == nil {
+			return len(batch), nil
+		}
+	}
+	return 0, err
+}
+
+func (w *HTTPWriter) post(body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for k, v := range w.opts.header {
+		req.Header[k] = v
+	}
+
+	resp, err := w.opts.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	// TimeKey the key of the time in the structured logs
+	TimeKey = "time"
+	// LevelKey the key of the level in the structured logs
+	LevelKey = "level"
+	// CallerKey the key of the file and line in the structured logs
+	CallerKey = "caller"
+	// MessageKey the key of the message in the structured logs
+	MessageKey = "msg"
+
+	// fieldKeyPrefix prefixes the fields which conflict with the keys above
+	fieldKeyPrefix = "fields."
+)
+
+// JSONFormatter represents a kind of formatter that formats the logs as json objects, one per line
+type JSONFormatter struct {
+	timeFormat string
+}
+
+// NewJSONFormatter returns a JSONFormatter, the format of time is time.RFC3339
+func NewJSONFormatter() *JSONFormatter {
+	return &JSONFormatter{
+		timeFormat: defaultTimeFormat,
+	}
+}
+
+// Format formats the logs as {"time": "...", "level": "...", "caller": "...", "msg": "...", fields...},
+// the fields are sorted by key and the ones conflict with the keys of the log are prefixed with "fields."
+func (j *JSONFormatter) Format(r *Record) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	buf.WriteByte('{')
+
+	writeJSON(buf, TimeKey, r.Time.Format(j.timeFormat))
+	writeJSON(buf, LevelKey, r.Level.String())
+	if len(r.Line) != 0 {
+		writeJSON(buf, CallerKey, r.Line)
+	}
+	writeJSON(buf, MessageKey, r.Message)
+
+	for _, key := range sortedKeys(r.Fields) {
+		name := key
+		switch key {
+		case TimeKey, LevelKey, CallerKey, MessageKey:
+			name = fieldKeyPrefix + key
+		}
+		writeJSON(buf, name, r.Fields[key])
+	}
+
+	buf.WriteString("}\n")
+	return buf.Bytes(), nil
+}
+
+// SetTimeFormat sets time format of JSONFormatter if the parameter fmt is not null
+func (j *JSONFormatter) SetTimeFormat(fmt string) {
+	if len(fmt) != 0 {
+		j.timeFormat = fmt
+	}
+}
+
+func writeJSON(buf *bytes.Buffer, key string, value interface{}) {
+	if buf.Len() > 1 {
+		buf.WriteByte(',')
+	}
+
+	k, _ := json.Marshal(key)
+	buf.Write(k)
+	buf.WriteByte(':')
+
+	if err, ok := value.(error); ok {
+		value = err.Error()
+	}
+	v, err := json.Marshal(value)
+	if err != nil {
+		// the values which can not be marshaled, e.g. channels, are rendered as text
+		v, _ = json.Marshal(fmt.Sprintf("%v", value))
+	}
+	buf.Write(v)
+}
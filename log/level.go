@@ -3,6 +3,7 @@ package log
 import (
 	"fmt"
 	"strings"
+	"sync/atomic"
 )
 
 type Level int
@@ -34,7 +35,41 @@ func (l Level) string() (lvl string) {
 	return
 }
 
-func parseLevel(lvl string) (level Level, err error) {
+// String returns the name of the level, e.g. "debug"
+func (l Level) String() string {
+	switch l {
+	case DebugLevel:
+		return "debug"
+	case InfoLevel:
+		return "info"
+	case WarningLevel:
+		return "warning"
+	case ErrorLevel:
+		return "error"
+	case FatalLevel:
+		return "fatal"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalText marshals the level as its name
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText unmarshals the level from its name or abbreviation
+func (l *Level) UnmarshalText(text []byte) error {
+	level, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*l = level
+	return nil
+}
+
+// ParseLevel parses the level from its name or abbreviation, e.g. "debug" or "d"
+func ParseLevel(lvl string) (level Level, err error) {
 	switch strings.ToLower(lvl) {
 	case "d":
 		fallthrough
@@ -62,3 +97,22 @@ func parseLevel(lvl string) (level Level, err error) {
 
 	return
 }
+
+// levelVar is the level which can be changed concurrently, it is shared by
+// the logger set it and the loggers cloned from the logger
+type levelVar struct {
+	v     int32
+	owner *Logger
+}
+
+func newLevelVar(lvl Level, owner *Logger) *levelVar {
+	return &levelVar{v: int32(lvl), owner: owner}
+}
+
+func (v *levelVar) get() Level {
+	return Level(atomic.LoadInt32(&v.v))
+}
+
+func (v *levelVar) set(lvl Level) {
+	atomic.StoreInt32(&v.v, int32(lvl))
+}
@@ -0,0 +1,151 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	// TimeFormatUnix formats the time as the seconds since the epoch
+	TimeFormatUnix = "unix"
+	// TimeFormatUnixMilli formats the time as the milliseconds since the epoch
+	TimeFormatUnixMilli = "unixmilli"
+)
+
+// LogfmtKeys the keys of the log in logfmt, the empty ones keep the default
+type LogfmtKeys struct {
+	Time    string
+	Level   string
+	Caller  string
+	Message string
+}
+
+// LogfmtFormatter represents a kind of formatter that formats the logs as logfmt, e.g.
+// ts=2006-01-02T15:04:05Z level=info caller=cache/helper.go:42 msg="hello world" key=value
+type LogfmtFormatter struct {
+	timeFormat string
+	keys       LogfmtKeys
+}
+
+// NewLogfmtFormatter returns a LogfmtFormatter, the format of time is time.RFC3339
+// and the keys are ts, level, caller and msg
+func NewLogfmtFormatter() *LogfmtFormatter {
+	return &LogfmtFormatter{
+		timeFormat: defaultTimeFormat,
+		keys: LogfmtKeys{
+			Time:    "ts",
+			Level:   LevelKey,
+			Caller:  CallerKey,
+			Message: MessageKey,
+		},
+	}
+}
+
+// Format formats the logs as logfmt, the fields are sorted by key and the ones
+// conflict with the keys of the log are prefixed with "fields."
+func (f *LogfmtFormatter) Format(r *Record) ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	writeLogfmt(buf, f.keys.Time, f.formatTime(r))
+	writeLogfmt(buf, f.keys.Level, r.Level.String())
+	if len(r.Line) != 0 {
+		writeLogfmt(buf, f.keys.Caller, r.Line)
+	}
+	writeLogfmt(buf, f.keys.Message, r.Message)
+
+	for _, key := range sortedKeys(r.Fields) {
+		name := logfmtKey(key)
+		switch name {
+		case f.keys.Time, f.keys.Level, f.keys.Caller, f.keys.Message:
+			name = fieldKeyPrefix + name
+		}
+
+		value := r.Fields[key]
+		if err, ok := value.(error); ok {
+			value = err.Error()
+		}
+		writeLogfmt(buf, name, fmt.Sprintf("%v", value))
+	}
+
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+// SetTimeFormat sets time format of LogfmtFormatter if the parameter fmt is not null,
+// it is the layout of time.Format, TimeFormatUnix or TimeFormatUnixMilli
+func (f *LogfmtFormatter) SetTimeFormat(fmt string) {
+	if len(fmt) != 0 {
+		f.timeFormat = fmt
+	}
+}
+
+// SetKeys sets the keys of the log, the empty ones in keys are ignored
+func (f *LogfmtFormatter) SetKeys(keys LogfmtKeys) {
+	if len(keys.Time) != 0 {
+		f.keys.Time = logfmtKey(keys.Time)
+	}
+	if len(keys.Level) != 0 {
+		f.keys.Level = logfmtKey(keys.Level)
+	}
+	if len(keys.Caller) != 0 {
+		f.keys.Caller = logfmtKey(keys.Caller)
+	}
+	if len(keys.Message) != 0 {
+		f.keys.Message = logfmtKey(keys.Message)
+	}
+}
+
+func (f *LogfmtFormatter) formatTime(r *Record) string {
+	switch f.timeFormat {
+	case TimeFormatUnix:
+		return strconv.FormatInt(r.Time.Unix(), 10)
+	case TimeFormatUnixMilli:
+		return strconv.FormatInt(r.Time.UnixNano()/1e6, 10)
+	default:
+		return r.Time.Format(f.timeFormat)
+	}
+}
+
+func writeLogfmt(buf *bytes.Buffer, key, value string) {
+	if buf.Len() > 0 {
+		buf.WriteByte(' ')
+	}
+	buf.WriteString(key)
+	buf.WriteByte('=')
+
+	if needsQuote(value) {
+		buf.WriteString(strconv.Quote(value))
+	} else {
+		buf.WriteString(value)
+	}
+}
+
+// needsQuote returns true when the value is empty or contains the space, '=',
+// '"', '\' or the characters which are not printable
+func needsQuote(value string) bool {
+	if len(value) == 0 {
+		return true
+	}
+	for _, r := range value {
+		if r <= ' ' || r == '=' || r == '"' || r == '\\' || !unicode.IsPrint(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// logfmtKey replaces the characters which are invalid in the key with '_'
+func logfmtKey(key string) string {
+	if len(key) == 0 {
+		return "_"
+	}
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' || !unicode.IsPrint(r) {
+			return '_'
+		}
+		return r
+	}, key)
+}
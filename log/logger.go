@@ -5,10 +5,13 @@ import (
 	"io"
 	"os"
 	"runtime"
-	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	oteltrace "go.opentelemetry.io/otel/trace"
 )
 
 // NOTE: the default depth for the logger is 3 so that we can get the correct file and line when use the logger to log message
@@ -17,19 +20,35 @@ var logger = New(os.Stdout, NewTextFormatter(), WarningLevel, 3)
 const srcSeparator = "harbor" + string(os.PathSeparator) + "src"
 
 func init() {
+	// LOG_LEVELS overrides the levels of the named loggers, e.g. "config=debug,cache=warning"
+	for _, s := range strings.Split(os.Getenv("LOG_LEVELS"), ",") {
+		kv := strings.SplitN(strings.TrimSpace(s), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		if level, err := ParseLevel(kv[1]); err == nil {
+			SetNamedLevel(strings.TrimSpace(kv[0]), level)
+		}
+	}
+
+	// the secrets are redacted from the logs of the default logger unless LOG_REDACT=false
+	if redact, err := strconv.ParseBool(os.Getenv("LOG_REDACT")); err != nil || redact {
+		logger.SetRedactor(NewRedactor())
+	}
+
 	lvl := os.Getenv("LOG_LEVEL")
 	if len(lvl) == 0 {
-		logger.setLevel(InfoLevel)
+		logger.SetLevel(InfoLevel)
 		return
 	}
 
-	level, err := parseLevel(lvl)
+	level, err := ParseLevel(lvl)
 	if err != nil {
-		logger.setLevel(InfoLevel)
+		logger.SetLevel(InfoLevel)
 		return
 	}
 
-	logger.setLevel(level)
+	logger.SetLevel(level)
 }
 
 // Fields type alias to map[string]interface{}
@@ -39,13 +58,19 @@ type Fields = map[string]interface{}
 type Logger struct {
 	out       io.Writer
 	fmtter    Formatter
-	lvl       Level
+	lvl       atomic.Pointer[levelVar] // level shared with the cloned loggers until their own level is set
+	name      string                   // name of the logger, the level of the name overrides lvl
 	callDepth int
 	skipLine  bool
 	fields    map[string]interface{}
-	fieldsStr string
-	mu        *sync.Mutex // ptr here to share one sync.Mutex for clone method
-	fallback  *Logger     // fallback logger when current out fail
+	mu        *sync.Mutex                // ptr here to share one sync.Mutex for clone method
+	wmu       *sync.Mutex                // ptr here to share the lock of writing the output for clone method
+	fallback  *Logger                    // fallback logger when current out fail
+	span      oteltrace.Span             // span the error logs are recorded to as events
+	async     atomic.Pointer[asyncQueue] // queue the logs are written from asynchronously
+	sampler   atomic.Pointer[sampler]    // sampler of the logs shared with the cloned loggers
+	sinks     []*Sink                    // sinks of the multi-sink logger, out and fmtter are not used when it is set
+	redactor  atomic.Pointer[Redactor]   // redactor masks the secrets in the logs before formatting, it is only enabled for the default logger by default
 }
 
 // New returns a customized Logger
@@ -61,14 +86,17 @@ func New(out io.Writer, fmtter Formatter, lvl Level, options ...interface{}) *Lo
 		}
 	}
 
-	return &Logger{
+	l := &Logger{
 		out:       out,
 		fmtter:    fmtter,
-		lvl:       lvl,
 		callDepth: depth,
 		fields:    map[string]interface{}{},
 		mu:        &sync.Mutex{},
+		wmu:       &sync.Mutex{},
 	}
+	l.lvl.Store(newLevelVar(lvl, l))
+
+	return l
 }
 
 // DefaultLogger returns the default logger within the pkg, i.e. the one used in log.Infof....
@@ -76,22 +104,40 @@ func DefaultLogger() *Logger {
 	return logger
 }
 
-// SetFallback enable fallback when error happen
+// SetFallback enable fallback when error happen, the fallback is set to the
+// output too when it is the FallbackWriter, e.g. the HTTPWriter
 func (l *Logger) SetFallback(logger *Logger) {
 	l.fallback = logger
+	if fw, ok := l.out.(FallbackWriter); ok {
+		fw.SetFallback(logger)
+	}
+	for _, s := range l.sinks {
+		if fw, ok := s.Out.(FallbackWriter); ok {
+			fw.SetFallback(logger)
+		}
+	}
 }
 
 func (l *Logger) clone() *Logger {
-	return &Logger{
+	r := &Logger{
 		out:       l.out,
 		fmtter:    l.fmtter,
-		lvl:       l.lvl,
+		name:      l.name,
 		callDepth: l.callDepth,
 		skipLine:  l.skipLine,
 		fields:    l.fields,
-		fieldsStr: l.fieldsStr,
 		mu:        l.mu,
+		wmu:       l.wmu,
+		fallback:  l.fallback,
+		span:      l.span,
+		sinks:     l.sinks,
 	}
+	r.lvl.Store(l.lvl.Load())
+	r.async.Store(l.async.Load())
+	r.sampler.Store(l.sampler.Load())
+	r.redactor.Store(l.redactor.Load())
+
+	return r
 }
 
 // WithDepth returns cloned logger with new depth
@@ -115,19 +161,7 @@ func (l *Logger) WithFields(fields Fields) *Logger {
 			copyFields[key] = value
 		}
 
-		sortedKeys := make([]string, 0, len(copyFields))
-		for key := range copyFields {
-			sortedKeys = append(sortedKeys, key)
-		}
-		sort.Strings(sortedKeys)
-
-		parts := make([]string, 0, len(copyFields))
-		for _, key := range sortedKeys {
-			parts = append(parts, fmt.Sprintf(`%v="%v"`, key, copyFields[key]))
-		}
-
 		r.fields = copyFields
-		r.fieldsStr = "[" + strings.Join(parts, " ") + "]"
 	}
 
 	return r
@@ -144,26 +178,81 @@ func (l *Logger) SetOutput(out io.Writer) {
 	defer l.mu.Unlock()
 
 	l.out = out
+	if fw, ok := out.(FallbackWriter); ok && l.fallback != nil {
+		fw.SetFallback(l.fallback)
+	}
 }
 
-// setFormatter sets the formatter of Logger l
-func (l *Logger) setFormatter(fmtter Formatter) {
+// Named returns cloned logger with the name, the level set by SetNamedLevel for
+// the name or its parents, e.g. "config" for "config.db", overrides the level of l.
+// Use SetNamedLevel rather than SetLevel of the cloned logger to change the
+// level of all the loggers with the name
+func (l *Logger) Named(name string) *Logger {
+	r := l.clone()
+	r.name = name
+
+	return r
+}
+
+// Name returns the name of Logger l
+func (l *Logger) Name() string {
+	return l.name
+}
+
+// SetFormatter sets the formatter of Logger l
+func (l *Logger) SetFormatter(fmtter Formatter) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
 	l.fmtter = fmtter
 }
 
-// setLevel sets the level of Logger l
-func (l *Logger) setLevel(lvl Level) {
+// SetLevel sets the level of Logger l, the loggers cloned from l follow the
+// level unless their own level is set, the loggers l is cloned from are not changed
+func (l *Logger) SetLevel(lvl Level) {
+	if v := l.lvl.Load(); v.owner == l {
+		v.set(lvl)
+		return
+	}
+	l.lvl.Store(newLevelVar(lvl, l))
+}
+
+func (l *Logger) output(record *Record) (err error) {
+	if record.Fields == nil && len(l.fields) > 0 {
+		record.Fields = l.fields
+	}
+	record = l.redact(record)
+	if l.span != nil && record.Level >= ErrorLevel && spanEventsEnabled() {
+		addSpanEvent(l.span, record)
+	}
+	if async := l.async.Load(); async != nil && async.push(l, record) {
+		return nil
+	}
+	return l.write(record)
+}
+
+// write formats and writes the record to the output synchronously
+func (l *Logger) write(record *Record) (err error) {
 	l.mu.Lock()
-	defer l.mu.Unlock()
+	out, fmtter := l.out, l.fmtter
+	l.mu.Unlock()
 
-	l.lvl = lvl
+	if len(l.sinks) == 0 {
+		return l.writeTo(out, fmtter, record)
+	}
+	for _, s := range l.sinks {
+		if record.Level < s.Level {
+			continue
+		}
+		if e := l.writeTo(s.Out, s.Formatter, record); e != nil && err == nil {
+			err = e
+		}
+	}
+	return
 }
 
-func (l *Logger) output(record *Record) (err error) {
-	b, err := l.fmtter.Format(record)
+func (l *Logger) writeTo(out io.Writer, fmtter Formatter, record *Record) (err error) {
+	b, err := fmtter.Format(record)
 	if err != nil {
 		return
 	}
@@ -172,9 +261,13 @@ func (l *Logger) output(record *Record) (err error) {
 			_ = l.fallback.output(record)
 		}
 	}()
-	l.mu.Lock()
-	defer l.mu.Unlock()
-	_, err = l.out.Write(b)
+	l.wmu.Lock()
+	defer l.wmu.Unlock()
+	if rw, ok := out.(RecordWriter); ok {
+		_, err = rw.WriteRecord(record, b)
+	} else {
+		_, err = out.Write(b)
+	}
 	if err != nil && l.fallback != nil {
 		_ = l.fallback.output(record)
 	}
@@ -183,15 +276,18 @@ func (l *Logger) output(record *Record) (err error) {
 
 // Debug ...
 func (l *Logger) Debug(v ...interface{}) {
-	if l.lvl <= DebugLevel {
-		record := NewRecord(time.Now(), fmt.Sprint(v...), l.getLine(), DebugLevel)
-		_ = l.output(record)
+	if l.GetLevel() <= DebugLevel {
+		msg := fmt.Sprint(v...)
+		if l.sampled(DebugLevel, msg) {
+			record := NewRecord(time.Now(), msg, l.getLine(), DebugLevel)
+			_ = l.output(record)
+		}
 	}
 }
 
 // Debugf ...
 func (l *Logger) Debugf(format string, v ...interface{}) {
-	if l.lvl <= DebugLevel {
+	if l.GetLevel() <= DebugLevel && l.sampled(DebugLevel, format) {
 		record := NewRecord(time.Now(), fmt.Sprintf(format, v...), l.getLine(), DebugLevel)
 		_ = l.output(record)
 	}
@@ -199,15 +295,18 @@ func (l *Logger) Debugf(format string, v ...interface{}) {
 
 // Info ...
 func (l *Logger) Info(v ...interface{}) {
-	if l.lvl <= InfoLevel {
-		record := NewRecord(time.Now(), fmt.Sprint(v...), l.getLine(), InfoLevel)
-		_ = l.output(record)
+	if l.GetLevel() <= InfoLevel {
+		msg := fmt.Sprint(v...)
+		if l.sampled(InfoLevel, msg) {
+			record := NewRecord(time.Now(), msg, l.getLine(), InfoLevel)
+			_ = l.output(record)
+		}
 	}
 }
 
 // Infof ...
 func (l *Logger) Infof(format string, v ...interface{}) {
-	if l.lvl <= InfoLevel {
+	if l.GetLevel() <= InfoLevel && l.sampled(InfoLevel, format) {
 		record := NewRecord(time.Now(), fmt.Sprintf(format, v...), l.getLine(), InfoLevel)
 		_ = l.output(record)
 	}
@@ -215,15 +314,18 @@ func (l *Logger) Infof(format string, v ...interface{}) {
 
 // Warning ...
 func (l *Logger) Warning(v ...interface{}) {
-	if l.lvl <= WarningLevel {
-		record := NewRecord(time.Now(), fmt.Sprint(v...), l.getLine(), WarningLevel)
-		_ = l.output(record)
+	if l.GetLevel() <= WarningLevel {
+		msg := fmt.Sprint(v...)
+		if l.sampled(WarningLevel, msg) {
+			record := NewRecord(time.Now(), msg, l.getLine(), WarningLevel)
+			_ = l.output(record)
+		}
 	}
 }
 
 // Warningf ...
 func (l *Logger) Warningf(format string, v ...interface{}) {
-	if l.lvl <= WarningLevel {
+	if l.GetLevel() <= WarningLevel && l.sampled(WarningLevel, format) {
 		record := NewRecord(time.Now(), fmt.Sprintf(format, v...), l.getLine(), WarningLevel)
 		_ = l.output(record)
 	}
@@ -231,15 +333,18 @@ func (l *Logger) Warningf(format string, v ...interface{}) {
 
 // Error ...
 func (l *Logger) Error(v ...interface{}) {
-	if l.lvl <= ErrorLevel {
-		record := NewRecord(time.Now(), fmt.Sprint(v...), l.getLine(), ErrorLevel)
-		_ = l.output(record)
+	if l.GetLevel() <= ErrorLevel {
+		msg := fmt.Sprint(v...)
+		if l.sampled(ErrorLevel, msg) {
+			record := NewRecord(time.Now(), msg, l.getLine(), ErrorLevel)
+			_ = l.output(record)
+		}
 	}
 }
 
 // Errorf ...
 func (l *Logger) Errorf(format string, v ...interface{}) {
-	if l.lvl <= ErrorLevel {
+	if l.GetLevel() <= ErrorLevel && l.sampled(ErrorLevel, format) {
 		record := NewRecord(time.Now(), fmt.Sprintf(format, v...), l.getLine(), ErrorLevel)
 		_ = l.output(record)
 	}
@@ -247,40 +352,39 @@ func (l *Logger) Errorf(format string, v ...interface{}) {
 
 // Fatal ...
 func (l *Logger) Fatal(v ...interface{}) {
-	if l.lvl <= FatalLevel {
+	if l.GetLevel() <= FatalLevel {
 		record := NewRecord(time.Now(), fmt.Sprint(v...), l.getLine(), FatalLevel)
 		_ = l.output(record)
 	}
+	l.Flush()
 	os.Exit(1)
 }
 
 // Fatalf ...
 func (l *Logger) Fatalf(format string, v ...interface{}) {
-	if l.lvl <= FatalLevel {
+	if l.GetLevel() <= FatalLevel {
 		record := NewRecord(time.Now(), fmt.Sprintf(format, v...), l.getLine(), FatalLevel)
 		_ = l.output(record)
 	}
+	l.Flush()
 	os.Exit(1)
 }
 
 // GetLevel returns the verbosity level of this logger
 func (l *Logger) GetLevel() Level {
-	return l.lvl
+	if len(l.name) > 0 {
+		if lvl, ok := namedLevel(l.name); ok {
+			return lvl
+		}
+	}
+	return l.lvl.Load().get()
 }
 
 func (l *Logger) getLine() string {
-	var str string
-	if !l.skipLine {
-		str = line(l.callDepth)
+	if l.skipLine {
+		return ""
 	}
-
-	str = str + l.fieldsStr
-
-	if str != "" {
-		str = str + ":"
-	}
-
-	return str
+	return line(l.callDepth)
 }
 
 // Debug ...
@@ -338,6 +442,21 @@ func GetLevel() Level {
 	return logger.GetLevel()
 }
 
+// SetLevel sets the verbosity level of default logger
+func SetLevel(lvl Level) {
+	logger.SetLevel(lvl)
+}
+
+// SetFormatter sets the formatter of default logger
+func SetFormatter(fmtter Formatter) {
+	logger.SetFormatter(fmtter)
+}
+
+// Named returns the default logger with the name
+func Named(name string) *Logger {
+	return logger.Named(name)
+}
+
 func line(callDepth int) string {
 	_, file, line, ok := runtime.Caller(callDepth)
 	if !ok {
@@ -345,9 +464,14 @@ func line(callDepth int) string {
 
 		line = 0
 	}
+	return caller(file, line)
+}
+
+// caller returns the "file:line" of the caller, the file is relative to srcSeparator
+func caller(file string, line int) string {
 	l := strings.SplitN(file, srcSeparator, 2)
 	if len(l) > 1 {
 		file = l[1]
 	}
-	return fmt.Sprintf("[%s:%d]", file, line)
+	return fmt.Sprintf("%s:%d", file, line)
 }
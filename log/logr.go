@@ -0,0 +1,101 @@
+package log
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// LogrSink is the logr.LogSink writes the logs by the Logger, the V-level 0
+// is InfoLevel and the higher ones are DebugLevel, the names are joined by "."
+// as the name of the Logger so the named levels apply
+type LogrSink struct {
+	l         *Logger
+	callDepth int
+}
+
+var (
+	_ logr.LogSink          = &LogrSink{}
+	_ logr.CallDepthLogSink = &LogrSink{}
+)
+
+// NewLogrSink returns the LogrSink of Logger l
+func NewLogrSink(l *Logger) *LogrSink {
+	return &LogrSink{l: l}
+}
+
+// NewLogr returns the logr.Logger writes the logs by Logger l
+func NewLogr(l *Logger) logr.Logger {
+	return logr.New(NewLogrSink(l))
+}
+
+// Init ...
+func (s *LogrSink) Init(info logr.RuntimeInfo) {
+	s.callDepth = info.CallDepth
+}
+
+// Enabled ...
+func (s *LogrSink) Enabled(level int) bool {
+	return s.l.GetLevel() <= fromLogrLevel(level)
+}
+
+// Info ...
+func (s *LogrSink) Info(level int, msg string, keysAndValues ...interface{}) {
+	// the frames: line, Info, logr.Logger.Info and the ones added by logr
+	s.withValues(keysAndValues).emit(time.Now(), fromLogrLevel(level), msg, line(2+s.callDepth))
+}
+
+// Error ...
+func (s *LogrSink) Error(err error, msg string, keysAndValues ...interface{}) {
+	l := s.withValues(keysAndValues)
+	if err != nil {
+		l = l.WithField("error", err)
+	}
+	l.emit(time.Now(), ErrorLevel, msg, line(2+s.callDepth))
+}
+
+// WithValues ...
+func (s *LogrSink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	return &LogrSink{l: s.withValues(keysAndValues), callDepth: s.callDepth}
+}
+
+// WithName ...
+func (s *LogrSink) WithName(name string) logr.LogSink {
+	if len(s.l.Name()) > 0 {
+		name = s.l.Name() + "." + name
+	}
+	return &LogrSink{l: s.l.Named(name), callDepth: s.callDepth}
+}
+
+// WithCallDepth ...
+func (s *LogrSink) WithCallDepth(depth int) logr.LogSink {
+	return &LogrSink{l: s.l, callDepth: s.callDepth + depth}
+}
+
+func (s *LogrSink) withValues(keysAndValues []interface{}) *Logger {
+	if len(keysAndValues) == 0 {
+		return s.l
+	}
+
+	fields := make(Fields, (len(keysAndValues)+1)/2)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		key, ok := keysAndValues[i].(string)
+		if !ok {
+			key = fmt.Sprintf("%v", keysAndValues[i])
+		}
+		if i+1 < len(keysAndValues) {
+			fields[key] = keysAndValues[i+1]
+		} else {
+			fields[key] = "(MISSING)"
+		}
+	}
+	return s.l.WithFields(fields)
+}
+
+func fromLogrLevel(level int) Level {
+	if level > 0 {
+		return DebugLevel
+	}
+	return InfoLevel
+}
@@ -0,0 +1,62 @@
+package log
+
+import (
+	"strings"
+	"sync"
+)
+
+var (
+	namedLevelsMU sync.RWMutex
+	namedLevels   = make(map[string]Level)
+)
+
+// SetNamedLevel overrides the level of the loggers with the name and its
+// children, e.g. the level of "config" applies to "config.db" too
+func SetNamedLevel(name string, lvl Level) {
+	namedLevelsMU.Lock()
+	defer namedLevelsMU.Unlock()
+
+	namedLevels[name] = lvl
+}
+
+// UnsetNamedLevel removes the level override of the name
+func UnsetNamedLevel(name string) {
+	namedLevelsMU.Lock()
+	defer namedLevelsMU.Unlock()
+
+	delete(namedLevels, name)
+}
+
+// NamedLevels returns the copy of the level overrides of the names
+func NamedLevels() map[string]Level {
+	namedLevelsMU.RLock()
+	defer namedLevelsMU.RUnlock()
+
+	levels := make(map[string]Level, len(namedLevels))
+	for name, lvl := range namedLevels {
+		levels[name] = lvl
+	}
+	return levels
+}
+
+// namedLevel returns the level of the name, the most specific one is returned
+// when the overrides of the name and its parents are set
+func namedLevel(name string) (Level, bool) {
+	namedLevelsMU.RLock()
+	defer namedLevelsMU.RUnlock()
+
+	if len(namedLevels) == 0 {
+		return 0, false
+	}
+
+	for {
+		if lvl, ok := namedLevels[name]; ok {
+			return lvl, true
+		}
+		i := strings.LastIndex(name, ".")
+		if i < 0 {
+			return 0, false
+		}
+		name = name[:i]
+	}
+}
@@ -4,10 +4,11 @@ import "time"
 
 // Record holds information about log
 type Record struct {
-	Time    time.Time // time when the log produced
-	Message string    // content of the log
-	Line    string    // in which file and line that the log produced
-	Level   Level     // level of the log
+	Time    time.Time              // time when the log produced
+	Message string                 // content of the log
+	Line    string                 // in which file and line that the log produced, e.g. "cache/helper.go:42"
+	Level   Level                  // level of the log
+	Fields  map[string]interface{} // fields of the logger, rendered by the formatter
 }
 
 func NewRecord(time time.Time, message, line string, level Level) *Record {
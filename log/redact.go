@@ -0,0 +1,202 @@
+package log
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// RedactedMask replaces the secrets in the logs
+const RedactedMask = "xxxxx"
+
+var (
+	// the password in the userinfo of the url, e.g. redis://:password@redis:6379
+	urlPasswordPattern = regexp.MustCompile(`([a-zA-Z][a-zA-Z0-9+.-]*://[^:/@\s]*:)([^@/\s]+)(@)`)
+	// the value of the headers, e.g. "Authorization: Bearer token" or map[Harbor-Secret:[secret]]
+	headerPattern = regexp.MustCompile(`(?i)((?:authorization|harbor-secret)"?\s*[:=]\s*\[?"?)((?:basic|bearer|digest)\s+)?([^"\s,;}\]]+)`)
+
+	defaultRedactFields = []string{"password", "secret", "token", "authorization", "harbor-secret"}
+)
+
+type redactOptions struct {
+	fields   []string
+	patterns []*regexp.Regexp
+}
+
+// RedactOption the option of the Redactor
+type RedactOption func(*redactOptions)
+
+// RedactFields masks the fields with the names, case insensitively, and the
+// "name: value" or "name=value" in the messages, they are added to the
+// default names: password, secret, token, authorization and harbor-secret
+func RedactFields(names ...string) RedactOption {
+	return func(o *redactOptions) {
+		o.fields = append(o.fields, names...)
+	}
+}
+
+// RedactPatterns masks the matches of the patterns in the messages and the
+// string values of the fields, only the first subexpression is masked when
+// the pattern has subexpressions, e.g. `ak=(\w+)`
+func RedactPatterns(patterns ...*regexp.Regexp) RedactOption {
+	return func(o *redactOptions) {
+		o.patterns = append(o.patterns, patterns...)
+	}
+}
+
+// Redactor masks the secrets in the logs, the passwords in the urls and the
+// values of the Authorization and Harbor-Secret headers are always masked
+type Redactor struct {
+	fields   map[string]struct{}
+	named    *regexp.Regexp
+	patterns []*regexp.Regexp
+}
+
+// NewRedactor returns the Redactor with the options
+func NewRedactor(opts ...RedactOption) *Redactor {
+	o := &redactOptions{fields: append([]string{}, defaultRedactFields...)}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	r := &Redactor{
+		fields:   make(map[string]struct{}, len(o.fields)),
+		patterns: o.patterns,
+	}
+	names := make([]string, 0, len(o.fields))
+	for _, name := range o.fields {
+		name = strings.ToLower(name)
+		if _, ok := r.fields[name]; ok || len(name) == 0 {
+			continue
+		}
+		r.fields[name] = struct{}{}
+		// the headers are masked by the headerPattern in the messages
+		if name != "authorization" && name != "harbor-secret" {
+			names = append(names, regexp.QuoteMeta(name))
+		}
+	}
+	if len(names) > 0 {
+		r.named = regexp.MustCompile(`(?i)(\b(?:` + strings.Join(names, "|") + `)"?\s*[:=]\s*\[?"?)([^"\s,;&}\]]+)`)
+	}
+	return r
+}
+
+// Redact returns the copy of the record whose secrets are masked, the record
+// is returned when there is no secret in it
+func (r *Redactor) Redact(record *Record) *Record {
+	msg := r.redact(record.Message)
+
+	var fields map[string]interface{}
+	for key, value := range record.Fields {
+		masked, ok := r.redactField(key, value)
+		if !ok {
+			continue
+		}
+		if fields == nil {
+			fields = make(map[string]interface{}, len(record.Fields))
+			for k, v := range record.Fields {
+				fields[k] = v
+			}
+		}
+		fields[key] = masked
+	}
+
+	if msg == record.Message && fields == nil {
+		return record
+	}
+
+	c := *record
+	c.Message = msg
+	if fields != nil {
+		c.Fields = fields
+	}
+	return &c
+}
+
+// redactField returns the masked value and true when the field has secret
+func (r *Redactor) redactField(key string, value interface{}) (interface{}, bool) {
+	if _, ok := r.fields[strings.ToLower(key)]; ok {
+		return RedactedMask, true
+	}
+
+	var str string
+	switch v := value.(type) {
+	case string:
+		str = v
+	case error:
+		str = v.Error()
+	case fmt.Stringer:
+		str = v.String()
+	default:
+		return nil, false
+	}
+
+	masked := r.redact(str)
+	return masked, masked != str
+}
+
+func (r *Redactor) redact(s string) string {
+	if len(s) == 0 {
+		return s
+	}
+
+	// the urls, headers and named values all have ':' or '='
+	if strings.ContainsAny(s, ":=") {
+		s = urlPasswordPattern.ReplaceAllString(s, "${1}"+RedactedMask+"${3}")
+		s = headerPattern.ReplaceAllString(s, "${1}${2}"+RedactedMask)
+		if r.named != nil {
+			s = r.named.ReplaceAllString(s, "${1}"+RedactedMask)
+		}
+	}
+	for _, p := range r.patterns {
+		s = replaceMatches(p, s)
+	}
+	return s
+}
+
+// replaceMatches masks the first subexpression of the matches, or the whole
+// matches when there is no subexpression
+func replaceMatches(p *regexp.Regexp, s string) string {
+	group := 0
+	if p.NumSubexp() > 0 {
+		group = 1
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, m := range p.FindAllStringSubmatchIndex(s, -1) {
+		start, end := m[2*group], m[2*group+1]
+		if start < 0 {
+			continue
+		}
+		b.WriteString(s[last:start])
+		b.WriteString(RedactedMask)
+		last = end
+	}
+	if last == 0 {
+		return s
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// SetRedactor sets the redactor of Logger l and the loggers cloned from it
+// afterwards, the redaction is disabled when it is nil. The redaction is
+// enabled for the default logger with the default options unless LOG_REDACT=false,
+// it is disabled for the loggers created by New until the redactor is set
+func (l *Logger) SetRedactor(r *Redactor) {
+	l.redactor.Store(r)
+}
+
+// redact returns the record masked by the redactor of Logger l
+func (l *Logger) redact(record *Record) *Record {
+	if r := l.redactor.Load(); r != nil {
+		return r.Redact(record)
+	}
+	return record
+}
+
+// SetRedactor sets the redactor of default logger
+func SetRedactor(r *Redactor) {
+	logger.SetRedactor(r)
+}
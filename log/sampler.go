@@ -0,0 +1,144 @@
+package log
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxSummaryTemplates the max count of the templates listed in the summary
+const maxSummaryTemplates = 10
+
+// SamplingRule logs the first First logs of the same template in the interval,
+// then every Thereafter-th one, the others are suppressed when Thereafter is 0
+type SamplingRule struct {
+	First      int
+	Thereafter int
+}
+
+type samplingKey struct {
+	level    Level
+	template string
+}
+
+// sampler samples the logs by the level and the template, which is the format
+// of Debugf etc. or the message of Debug etc.
+type sampler struct {
+	l        *Logger
+	interval time.Duration
+	rules    map[Level]SamplingRule
+
+	mu         sync.Mutex
+	start      time.Time
+	counts     map[samplingKey]int
+	suppressed map[samplingKey]int
+	timer      *time.Timer
+}
+
+func (s *sampler) allow(lvl Level, template string) bool {
+	rule, ok := s.rules[lvl]
+	if !ok {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(s.start) >= s.interval {
+		s.start, s.counts = now, make(map[samplingKey]int)
+	}
+
+	key := samplingKey{level: lvl, template: template}
+	n := s.counts[key] + 1
+	s.counts[key] = n
+	if n <= rule.First || (rule.Thereafter > 0 && (n-rule.First)%rule.Thereafter == 0) {
+		return true
+	}
+
+	s.suppressed[key]++
+	if s.timer == nil {
+		s.timer = time.AfterFunc(s.start.Add(s.interval).Sub(now), s.summarize)
+	}
+	return false
+}
+
+// summarize writes the summary of the suppressed logs, the level of the
+// summary is the highest one of the suppressed logs
+func (s *sampler) summarize() {
+	s.mu.Lock()
+	suppressed := s.suppressed
+	s.suppressed, s.timer = make(map[samplingKey]int), nil
+	s.mu.Unlock()
+
+	if len(suppressed) == 0 {
+		return
+	}
+
+	keys := make([]samplingKey, 0, len(suppressed))
+	total, lvl := 0, DebugLevel
+	for key, n := range suppressed {
+		keys = append(keys, key)
+		total += n
+		if key.level > lvl {
+			lvl = key.level
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if suppressed[keys[i]] != suppressed[keys[j]] {
+			return suppressed[keys[i]] > suppressed[keys[j]]
+		}
+		return keys[i].template < keys[j].template
+	})
+
+	parts := make([]string, 0, maxSummaryTemplates)
+	for i, key := range keys {
+		if i == maxSummaryTemplates {
+			parts = append(parts, fmt.Sprintf("and %d more", len(keys)-i))
+			break
+		}
+		parts = append(parts, fmt.Sprintf("[%s] %q x%d", key.level.string(), key.template, suppressed[key]))
+	}
+
+	record := NewRecord(time.Now(), fmt.Sprintf("suppressed %d logs by sampling: %s", total, strings.Join(parts, ", ")), "", lvl)
+	record.Fields = Fields{"suppressed": total}
+	_ = s.l.write(s.l.redact(record))
+}
+
+// SetSampling samples the logs of Logger l and the loggers cloned from it
+// afterwards by the rules of the levels in every interval, the logs of the
+// levels without rule and the fatal logs are not sampled. The sampling is
+// disabled when the interval is 0 or there is no rule
+func (l *Logger) SetSampling(interval time.Duration, rules map[Level]SamplingRule) {
+	var s *sampler
+	if interval > 0 && len(rules) > 0 {
+		s = &sampler{
+			l:          l,
+			interval:   interval,
+			rules:      make(map[Level]SamplingRule, len(rules)),
+			counts:     make(map[samplingKey]int),
+			suppressed: make(map[samplingKey]int),
+		}
+		for lvl, rule := range rules {
+			// the fatal log explains why the process exits
+			if lvl != FatalLevel {
+				s.rules[lvl] = rule
+			}
+		}
+	}
+
+	l.sampler.Store(s)
+}
+
+// sampled returns false when the log is suppressed by the sampling
+func (l *Logger) sampled(lvl Level, template string) bool {
+	s := l.sampler.Load()
+	return s == nil || s.allow(lvl, template)
+}
+
+// SetSampling samples the logs of default logger
+func SetSampling(interval time.Duration, rules map[Level]SamplingRule) {
+	logger.SetSampling(interval, rules)
+}
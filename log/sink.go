@@ -0,0 +1,44 @@
+package log
+
+import (
+	"io"
+	"os"
+)
+
+// Sink is the output of the multi-sink logger, the logs whose level is lower
+// than the Level of the sink are not written to it
+type Sink struct {
+	Out       io.Writer
+	Formatter Formatter
+	Level     Level
+}
+
+// NewMulti returns the Logger writes the logs to all the sinks, the level of
+// the logger is the lowest one of the sinks. The nil Out of the sink is
+// os.Stdout and the nil Formatter is the TextFormatter. SetOutput and
+// SetFormatter have no effect on the sinks
+func NewMulti(sinks ...*Sink) *Logger {
+	if len(sinks) == 0 {
+		return New(os.Stdout, NewTextFormatter(), InfoLevel)
+	}
+
+	copied := make([]*Sink, 0, len(sinks))
+	lvl := FatalLevel
+	for _, s := range sinks {
+		c := *s
+		if c.Out == nil {
+			c.Out = os.Stdout
+		}
+		if c.Formatter == nil {
+			c.Formatter = NewTextFormatter()
+		}
+		if c.Level < lvl {
+			lvl = c.Level
+		}
+		copied = append(copied, &c)
+	}
+
+	l := New(copied[0].Out, copied[0].Formatter, lvl)
+	l.sinks = copied
+	return l
+}
@@ -0,0 +1,118 @@
+package log
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"time"
+
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// SlogHandler is the slog.Handler writes the logs by the Logger, the slog
+// levels are mapped to the nearest lower levels, e.g. slog.LevelWarn-1 is
+// InfoLevel, the attributes are the fields whose keys are prefixed by the
+// groups, e.g. "group.key", and the trace_id and span_id fields are added when
+// there is span in the context
+type SlogHandler struct {
+	l      *Logger
+	prefix string
+}
+
+var _ slog.Handler = &SlogHandler{}
+
+// NewSlogHandler returns the SlogHandler of Logger l
+func NewSlogHandler(l *Logger) *SlogHandler {
+	return &SlogHandler{l: l}
+}
+
+// Enabled ...
+func (h *SlogHandler) Enabled(_ context.Context, lvl slog.Level) bool {
+	return h.l.GetLevel() <= fromSlogLevel(lvl)
+}
+
+// Handle ...
+func (h *SlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	l := h.l.WithSpan(oteltrace.SpanFromContext(ctx))
+	if r.NumAttrs() > 0 {
+		fields := Fields{}
+		r.Attrs(func(a slog.Attr) bool {
+			addSlogAttr(fields, h.prefix, a)
+			return true
+		})
+		l = l.WithFields(fields)
+	}
+
+	var line string
+	if r.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		line = caller(frame.File, frame.Line)
+	}
+
+	t := r.Time
+	if t.IsZero() {
+		t = time.Now()
+	}
+	l.emit(t, fromSlogLevel(r.Level), r.Message, line)
+	return nil
+}
+
+// WithAttrs ...
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	fields := Fields{}
+	for _, a := range attrs {
+		addSlogAttr(fields, h.prefix, a)
+	}
+	return &SlogHandler{l: h.l.WithFields(fields), prefix: h.prefix}
+}
+
+// WithGroup ...
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if len(name) == 0 {
+		return h
+	}
+	return &SlogHandler{l: h.l, prefix: h.prefix + name + "."}
+}
+
+func addSlogAttr(fields Fields, prefix string, a slog.Attr) {
+	v := a.Value.Resolve()
+	if v.Kind() == slog.KindGroup {
+		p := prefix
+		if len(a.Key) > 0 {
+			p = prefix + a.Key + "."
+		}
+		for _, ga := range v.Group() {
+			addSlogAttr(fields, p, ga)
+		}
+		return
+	}
+	if len(a.Key) == 0 {
+		return
+	}
+	fields[prefix+a.Key] = v.Any()
+}
+
+func fromSlogLevel(lvl slog.Level) Level {
+	switch {
+	case lvl < slog.LevelInfo:
+		return DebugLevel
+	case lvl < slog.LevelWarn:
+		return InfoLevel
+	case lvl < slog.LevelError:
+		return WarningLevel
+	default:
+		return ErrorLevel
+	}
+}
+
+// emit outputs the message with the caller line, it is used by the adapters
+// of the other logging interfaces which find the callers by themselves
+func (l *Logger) emit(t time.Time, lvl Level, msg, line string) {
+	if l.GetLevel() > lvl || !l.sampled(lvl, msg) {
+		return
+	}
+	if l.skipLine {
+		line = ""
+	}
+	_ = l.output(NewRecord(t, msg, line, lvl))
+}
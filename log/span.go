@@ -0,0 +1,75 @@
+package log
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/attribute"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// TraceIDKey the key of the field of the trace id
+	TraceIDKey = "trace_id"
+	// SpanIDKey the key of the field of the span id
+	SpanIDKey = "span_id"
+)
+
+var spanEvents int32
+
+// EnableSpanEvents enables or disables recording the error and fatal logs as
+// the events of the span of the logger, it is disabled by default
+func EnableSpanEvents(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&spanEvents, v)
+}
+
+func spanEventsEnabled() bool {
+	return atomic.LoadInt32(&spanEvents) == 1
+}
+
+// WithSpan returns cloned logger with the trace_id and span_id fields of the
+// span, Logger l is returned when the span context is invalid
+func (l *Logger) WithSpan(span oteltrace.Span) *Logger {
+	if span == nil {
+		return l
+	}
+	sc := span.SpanContext()
+	if !sc.IsValid() {
+		return l
+	}
+
+	r := l.WithFields(Fields{
+		TraceIDKey: sc.TraceID().String(),
+		SpanIDKey:  sc.SpanID().String(),
+	})
+	r.span = span
+
+	return r
+}
+
+func addSpanEvent(span oteltrace.Span, record *Record) {
+	if !span.IsRecording() {
+		return
+	}
+
+	attrs := make([]attribute.KeyValue, 0, len(record.Fields)+3)
+	attrs = append(attrs,
+		attribute.String("log.severity", record.Level.String()),
+		attribute.String("log.message", record.Message),
+	)
+	if len(record.Line) != 0 {
+		attrs = append(attrs, attribute.String("code.line", record.Line))
+	}
+	for _, key := range sortedKeys(record.Fields) {
+		if key == TraceIDKey || key == SpanIDKey {
+			continue
+		}
+		attrs = append(attrs, attribute.String("log.fields."+key, fmt.Sprintf("%v", record.Fields[key])))
+	}
+
+	span.AddEvent("log", oteltrace.WithAttributes(attrs...))
+}
@@ -0,0 +1,269 @@
+package log
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+const syslogTimeFormat = "2006-01-02T15:04:05.000000Z07:00"
+
+// Facility the syslog facility
+type Facility int
+
+// the syslog facilities
+const (
+	FacilityKern Facility = iota
+	FacilityUser
+	FacilityMail
+	FacilityDaemon
+	FacilityAuth
+	FacilitySyslog
+	FacilityLocal0 Facility = iota + 10
+	FacilityLocal1
+	FacilityLocal2
+	FacilityLocal3
+	FacilityLocal4
+	FacilityLocal5
+	FacilityLocal6
+	FacilityLocal7
+)
+
+// Severity returns the syslog severity of the level
+func (l Level) Severity() int {
+	switch l {
+	case DebugLevel:
+		return 7 // debug
+	case InfoLevel:
+		return 6 // informational
+	case WarningLevel:
+		return 4 // warning
+	case ErrorLevel:
+		return 3 // error
+	case FatalLevel:
+		return 2 // critical
+	default:
+		return 5 // notice
+	}
+}
+
+type syslogOptions struct {
+	bufferOptions
+	facility  Facility
+	appName   string
+	hostname  string
+	tlsConfig *tls.Config
+	timeout   time.Duration
+}
+
+// SyslogOption the option of the SyslogWriter
+type SyslogOption func(*syslogOptions)
+
+// SyslogFacility sets the facility, FacilityLocal0 by default
+func SyslogFacility(f Facility) SyslogOption {
+	return func(o *syslogOptions) {
+		o.facility = f
+	}
+}
+
+// SyslogAppName sets the app name, the name of the executable by default
+func SyslogAppName(name string) SyslogOption {
+	return func(o *syslogOptions) {
+		o.appName = name
+	}
+}
+
+// SyslogHostname sets the hostname, os.Hostname() by default
+func SyslogHostname(hostname string) SyslogOption {
+	return func(o *syslogOptions) {
+		o.hostname = hostname
+	}
+}
+
+// SyslogTLSConfig sets the tls config of the "tls" network
+func SyslogTLSConfig(cfg *tls.Config) SyslogOption {
+	return func(o *syslogOptions) {
+		o.tlsConfig = cfg
+	}
+}
+
+// SyslogTimeout sets the timeout of dialing and writing, 5s by default
+func SyslogTimeout(timeout time.Duration) SyslogOption {
+	return func(o *syslogOptions) {
+		o.timeout = timeout
+	}
+}
+
+// SyslogBufferSize sets the count of the logs buffered before sending, 1024 by default
+func SyslogBufferSize(size int) SyslogOption {
+	return func(o *syslogOptions) {
+		o.bufferSize = size
+	}
+}
+
+// SyslogBlockTimeout sets how long the write blocks when the buffer is full, then
+// ErrBufferFull is returned and the log is written to the fallback logger, 100ms by default
+func SyslogBlockTimeout(timeout time.Duration) SyslogOption {
+	return func(o *syslogOptions) {
+		o.blockTimeout = timeout
+	}
+}
+
+// SyslogWriter writes the logs to the syslog collector in RFC 5424 format over
+// "udp", "tcp" or "tls", the messages are framed by octet counting on the
+// stream networks. It is the RecordWriter so the level is mapped to the
+// severity, the logs written by Write are informational. The logs are buffered
+// and sent in the background, the logs which fail to be sent are written to
+// the fallback logger
+type SyslogWriter struct {
+	*bufferedWriter
+
+	network string
+	addr    string
+	opts    *syslogOptions
+	pid     string
+
+	// the conn is only used by the background goroutine
+	conn net.Conn
+}
+
+// NewSyslogWriter returns the SyslogWriter connects to the address of the network
+func NewSyslogWriter(network, addr string, opts ...SyslogOption) (*SyslogWriter, error) {
+	o := &syslogOptions{
+		bufferOptions: bufferOptions{
+			batchSize:     1,
+			flushInterval: defaultFlushInterval,
+			bufferSize:    defaultBufferSize,
+			blockTimeout:  defaultBlockTimeout,
+		},
+		facility: FacilityLocal0,
+		appName:  filepath.Base(os.Args[0]),
+		timeout:  5 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if len(o.hostname) == 0 {
+		o.hostname, _ = os.Hostname()
+	}
+	if o.timeout <= 0 {
+		o.timeout = 5 * time.Second
+	}
+
+	switch network {
+	case "udp", "tcp", "tls":
+	default:
+		return nil, fmt.Errorf("unsupported syslog network: %s", network)
+	}
+
+	w := &SyslogWriter{
+		network: network,
+		addr:    addr,
+		opts:    o,
+		pid:     strconv.Itoa(os.Getpid()),
+	}
+	if err := w.connect(); err != nil {
+		return nil, err
+	}
+	w.bufferedWriter = newBufferedWriter(network+"://"+addr, o.bufferOptions, w.send)
+	return w, nil
+}
+
+// Write buffers the log as informational message
+func (w *SyslogWriter) Write(p []byte) (int, error) {
+	return w.WriteRecord(NewRecord(time.Now(), string(bytes.TrimRight(p, "\n")), "", InfoLevel), p)
+}
+
+// Close sends the buffered logs, stops the writer and closes the connection
+func (w *SyslogWriter) Close() error {
+	_ = w.bufferedWriter.Close()
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
+
+func (w *SyslogWriter) send(batch []*bufferedEntry) (int, error) {
+	for i, e := range batch {
+		lvl, t := InfoLevel, time.Now()
+		if e.record != nil {
+			lvl, t = e.record.Level, e.record.Time
+		}
+		if err := w.write(w.message(lvl, t, e.data)); err != nil {
+			return i, err
+		}
+	}
+	return len(batch), nil
+}
+
+func (w *SyslogWriter) write(msg []byte) error {
+	// reconnect once when the connection is broken
+	var err error
+	for i := 0; i < 2; i++ {
+		if w.conn == nil {
+			if err = w.connect(); err != nil {
+				continue
+			}
+		}
+		_ = w.conn.SetWriteDeadline(time.Now().Add(w.opts.timeout))
+		if _, err = w.conn.Write(msg); err == nil {
+			return nil
+		}
+		_ = w.conn.Close()
+		w.conn = nil
+	}
+	return err
+}
+
+// message formats the log as "<PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID SD MSG"
+func (w *SyslogWriter) message(lvl Level, t time.Time, p []byte) []byte {
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "<%d>1 %s %s %s %s - - ",
+		int(w.opts.facility)*8+lvl.Severity(),
+		t.Format(syslogTimeFormat),
+		syslogField(w.opts.hostname),
+		syslogField(w.opts.appName),
+		w.pid,
+	)
+	buf.Write(bytes.TrimRight(p, "\n"))
+
+	if w.network == "udp" {
+		return buf.Bytes()
+	}
+	// octet counting framing, RFC 6587
+	return append([]byte(strconv.Itoa(buf.Len())+" "), buf.Bytes()...)
+}
+
+func (w *SyslogWriter) connect() error {
+	var conn net.Conn
+	var err error
+	dialer := &net.Dialer{Timeout: w.opts.timeout}
+	if w.network == "tls" {
+		conn, err = tls.DialWithDialer(dialer, "tcp", w.addr, w.opts.tlsConfig)
+	} else {
+		conn, err = dialer.Dial(w.network, w.addr)
+	}
+	if err != nil {
+		return err
+	}
+	w.conn = conn
+	return nil
+}
+
+// syslogField returns the header field, "-" is the nil value and the length is at most 48
+func syslogField(s string) string {
+	if len(s) == 0 {
+		return "-"
+	}
+	if len(s) > 48 {
+		s = s[:48]
+	}
+	return s
+}
@@ -2,6 +2,8 @@ package log
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -19,12 +21,19 @@ func NewTextFormatter() *TextFormatter {
 	}
 }
 
-// Format formats the logs as "time [level] line message"
+// Format formats the logs as "time [level] [line][fields]: message"
 func (t *TextFormatter) Format(r *Record) (b []byte, err error) {
 	s := fmt.Sprintf("%s [%s] ", r.Time.Format(t.timeFormat), r.Level.string())
 
+	var line string
 	if len(r.Line) != 0 {
-		s = s + r.Line + " "
+		line = "[" + r.Line + "]"
+	}
+	if len(r.Fields) != 0 {
+		line = line + "[" + textFields(r.Fields) + "]"
+	}
+	if len(line) != 0 {
+		s = s + line + ": "
 	}
 
 	if len(r.Message) != 0 {
@@ -46,3 +55,20 @@ func (t *TextFormatter) SetTimeFormat(fmt string) {
 		t.timeFormat = fmt
 	}
 }
+
+func textFields(fields map[string]interface{}) string {
+	parts := make([]string, 0, len(fields))
+	for _, key := range sortedKeys(fields) {
+		parts = append(parts, fmt.Sprintf(`%v="%v"`, key, fields[key]))
+	}
+	return strings.Join(parts, " ")
+}
+
+func sortedKeys(fields map[string]interface{}) []string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,13 @@
+package log
+
+// RecordWriter is the output which needs the record besides the formatted
+// log, e.g. the SyslogWriter maps the level of the record to the severity
+type RecordWriter interface {
+	WriteRecord(r *Record, p []byte) (int, error)
+}
+
+// FallbackWriter is the output which writes the logs to the fallback logger
+// when it fails asynchronously, e.g. the HTTPWriter fails to send the batch
+type FallbackWriter interface {
+	SetFallback(l *Logger)
+}
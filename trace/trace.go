@@ -101,6 +101,12 @@ func (s ShutdownFunc) Shutdown() {
 
 // Init initializes the trace provider
 func InitGlobalTracer(ctx context.Context) ShutdownFunc {
+	// write the internal logs and errors of OpenTelemetry by the logger
+	logger := log.Named("otel")
+	otel.SetLogger(log.NewLogr(logger))
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		logger.Errorf("opentelemetry error: %v", err)
+	}))
 	if !Enabled() {
 		otel.SetTracerProvider(oteltrace.NewNoopTracerProvider())
 		return func() {}